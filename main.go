@@ -23,7 +23,7 @@ func getDefaultConfigPath() string {
 }
 
 // loadConfig parses the config file named by the --config flag and expands
-// environment variables in ScanDirs. If positional args are provided they
+// environment variables in ScanDirs and NoRemote.Allow. If positional args are provided they
 // replace ScanDirs.Include.
 func loadConfig(cmd *cli.Command, defaultConfig string) (*scanner.Config, error) {
 	config, err := scanner.ParseConfigFile(cmd.Root().String("config"), defaultConfig)
@@ -39,6 +39,9 @@ func loadConfig(cmd *cli.Command, defaultConfig string) (*scanner.Config, error)
 	for i := range config.ScanDirs.Exclude {
 		config.ScanDirs.Exclude[i] = os.ExpandEnv(config.ScanDirs.Exclude[i])
 	}
+	for i := range config.NoRemote.Allow {
+		config.NoRemote.Allow[i] = os.ExpandEnv(config.NoRemote.Allow[i])
+	}
 	return config, nil
 }
 
@@ -59,6 +62,7 @@ func main() {
 		},
 		Commands: []*cli.Command{
 			reportCommand(),
+			pruneBranchesCommand(),
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/boyvinall/dirtygit/scanner"
 )
 
 func TestGetDefaultConfigPathUsesHomeAndSuffix(t *testing.T) {
@@ -11,3 +15,15 @@ func TestGetDefaultConfigPathUsesHomeAndSuffix(t *testing.T) {
 		t.Fatalf("expected path to end with .dirtygit.yml, got %q", p)
 	}
 }
+
+func TestRunPruneBranchesDryRunNothingFound(t *testing.T) {
+	cfg := &scanner.Config{}
+	cfg.ScanDirs.Include = []string{t.TempDir()}
+	var out bytes.Buffer
+	if err := runPruneBranches(context.Background(), cfg, true, &out); err != nil {
+		t.Fatalf("runPruneBranches: %v", err)
+	}
+	if got := out.String(); got != "No merged branches found.\n" {
+		t.Fatalf("output = %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// runPruneBranches deletes (or with dryRun, only lists) local branches already
+// merged into a remote default branch, across every scanned repository.
+func runPruneBranches(ctx context.Context, config *scanner.Config, dryRun bool, w io.Writer) error {
+	repos, err := scanner.ScanMergedBranches(ctx, config)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		fmt.Fprintln(w, "No merged branches found.")
+		return nil
+	}
+	failed := 0
+	for _, repo := range repos {
+		fmt.Fprintln(w, repo.Path)
+		for _, lb := range repo.Branches {
+			if dryRun {
+				fmt.Fprintf(w, "  would delete %s (merged into %s)\n", lb.Name, lb.MergedInto)
+				continue
+			}
+			if err := scanner.DeleteMergedBranch(repo.Path, lb.Name); err != nil {
+				fmt.Fprintf(w, "  failed %s: %v\n", lb.Name, err)
+				failed++
+				continue
+			}
+			fmt.Fprintf(w, "  deleted %s (was %s)\n", lb.Name, shortHash(lb.TipHash))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d branch(es) could not be deleted", failed)
+	}
+	return nil
+}
+
+func pruneBranchesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "prune-branches",
+		Usage: "Delete local branches already merged into the remote default branch",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "List the branches that would be deleted without deleting them",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			config, err := loadConfig(cmd, defaultConfig)
+			if err != nil {
+				return err
+			}
+			return runPruneBranches(ctx, config, cmd.Bool("dry-run"), os.Stdout)
+		},
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
@@ -18,6 +20,10 @@ type reportBranchEntry struct {
 	// ShownInTUI is true when this branch appears in the TUI Branches pane for its repository
 	ShownInTUI  bool `json:"shown_in_tui"`
 	IsLocalOnly bool `json:"is_local_only"`
+	// GoneMerged and GoneUnmerged split branches whose upstream was deleted into
+	// safe-to-delete (already in a remote default branch) and still-unique work.
+	GoneMerged   bool `json:"gone_merged"`
+	GoneUnmerged bool `json:"gone_unmerged"`
 }
 
 // reportFileEntry is one porcelain status entry for a file.
@@ -29,6 +35,40 @@ type reportFileEntry struct {
 	OriginalPath string `json:"original_path"`
 }
 
+// reportLostCommit is one reflog commit that no branch, tag, or remote-tracking ref can reach.
+type reportLostCommit struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	// Reflog is the reflog the commit was found in (e.g. "HEAD" or a branch name).
+	Reflog     string `json:"reflog"`
+	ReflogUnix int64  `json:"reflog_unix"`
+	CommitUnix int64  `json:"commit_unix"`
+}
+
+// reportReason is one condition that made a repository dirty (see the rules config).
+type reportReason struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+	// SinceUnix is when the condition started, or 0 when unknown.
+	SinceUnix int64 `json:"since_unix"`
+	// Rule names the config rule that included it; empty for the default policy.
+	Rule string `json:"rule"`
+}
+
+// reportScoreComponent is one input to a repository's risk score.
+type reportScoreComponent struct {
+	Name   string  `json:"name"`
+	Count  int     `json:"count"`
+	Weight float64 `json:"weight"`
+	Points float64 `json:"points"`
+}
+
+// reportScore is a repository's risk score and what it is made of (see the score config).
+type reportScore struct {
+	Total      float64                `json:"total"`
+	Components []reportScoreComponent `json:"components"`
+}
+
 // reportRepo is the per-repository section of the report.
 type reportRepo struct {
 	Path    string `json:"path"`
@@ -40,22 +80,44 @@ type reportRepo struct {
 	Detached      bool   `json:"detached"`
 	// Branches lists all local branches including those excluded by config (see ExcludedByConfig).
 	Branches []reportBranchEntry `json:"branches"`
+	// LostCommits are reflog commits unreachable from any ref (see lostcommits.maxage).
+	LostCommits []reportLostCommit `json:"lost_commits"`
+	// BehindOnly is true for repos listed only because of --include-behind.
+	BehindOnly bool `json:"behind_only"`
+	// IncomingCount is commits on remotes not yet in the matching local branches;
+	// NewestIncomingUnix is the committer date of the newest such remote tip (0 when none).
+	IncomingCount      int   `json:"incoming_count"`
+	NewestIncomingUnix int64 `json:"newest_incoming_unix"`
+	// NoRemote is true when the repo has commits but no configured remote;
+	// CommitCount and LastCommitUnix then describe its local branches.
+	NoRemote       bool  `json:"no_remote"`
+	CommitCount    int   `json:"commit_count"`
+	LastCommitUnix int64 `json:"last_commit_unix"`
+	// DirtySinceUnix approximates when the working tree became dirty (oldest
+	// changed-path mtime); OldestUnpushedUnix is the oldest unpushed commit on
+	// a shown branch (per-branch values are in Branches). 0 when none.
+	DirtySinceUnix     int64 `json:"dirty_since_unix"`
+	OldestUnpushedUnix int64 `json:"oldest_unpushed_unix"`
+	// Reasons are why the repository counts as dirty; empty for behind-only repos.
+	Reasons []reportReason `json:"reasons"`
+	// Score ranks how much unsaved work the repository holds; higher is riskier.
+	Score reportScore `json:"score"`
 }
 
 // report is the top-level JSON structure for the report subcommand.
 type report struct {
-	// Repos are the repositories shown in the TUI repository pane (dirty or diverged),
-	// in alphabetical order.
+	// Repos are the repositories shown in the TUI repository pane (dirty or diverged,
+	// plus behind-only repos with --include-behind), in alphabetical order.
 	Repos []reportRepo `json:"repos"`
 }
 
-func buildReport(mgs *scanner.MultiGitStatus) report {
+func buildReport(mgs *scanner.MultiGitStatus, includeBehind bool) report {
 	paths := mgs.SortedRepoPaths()
 	repos := make([]reportRepo, 0, len(paths))
 
 	for _, path := range paths {
 		rs, ok := mgs.Get(path)
-		if !ok {
+		if !ok || (rs.BehindOnly && !includeBehind) {
 			continue
 		}
 
@@ -84,29 +146,66 @@ func buildReport(mgs *scanner.MultiGitStatus) report {
 				LocalBranchRef: lb,
 				ShownInTUI:     survived,
 				IsLocalOnly:    lb.IsLocalOnly(),
+				GoneMerged:     lb.IsGoneMerged(),
+				GoneUnmerged:   lb.IsGoneUnmerged(),
+			})
+		}
+
+		lost := make([]reportLostCommit, 0, len(rs.LostCommits))
+		for _, lc := range rs.LostCommits {
+			lost = append(lost, reportLostCommit{
+				Hash:       lc.Hash,
+				Subject:    lc.Subject,
+				Reflog:     lc.Reflog,
+				ReflogUnix: lc.ReflogUnix,
+				CommitUnix: lc.CommitUnix,
+			})
+		}
+
+		reasons := make([]reportReason, 0, len(rs.Reasons))
+		for _, rr := range rs.Reasons {
+			reasons = append(reasons, reportReason{Kind: rr.Kind, Detail: rr.Detail, SinceUnix: rr.Unix, Rule: rr.Rule})
+		}
+
+		score := reportScore{Total: rs.Score.Total, Components: make([]reportScoreComponent, 0, len(rs.Score.Components))}
+		for _, sc := range rs.Score.Components {
+			score.Components = append(score.Components, reportScoreComponent{
+				Name: sc.Name, Count: sc.Count, Weight: sc.Weight, Points: sc.Points(),
 			})
 		}
 
+		incoming, newestIncoming := rs.Behind()
 		repos = append(repos, reportRepo{
-			Path:          path,
-			IsClean:       rs.Porcelain.ToGitStatus().IsClean(),
-			Files:         files,
-			CurrentBranch: rs.Branch,
-			Detached:      rs.Detached,
-			Branches:      branches,
+			Path:               path,
+			IsClean:            rs.Porcelain.ToGitStatus().IsClean(),
+			Files:              files,
+			CurrentBranch:      rs.Branch,
+			Detached:           rs.Detached,
+			Branches:           branches,
+			LostCommits:        lost,
+			BehindOnly:         rs.BehindOnly,
+			IncomingCount:      incoming,
+			NewestIncomingUnix: newestIncoming,
+			NoRemote:           rs.NoRemote,
+			CommitCount:        rs.CommitCount,
+			LastCommitUnix:     rs.LastCommitUnix,
+			DirtySinceUnix:     rs.DirtySinceUnix,
+			OldestUnpushedUnix: rs.OldestUnpushedUnix(),
+			Reasons:            reasons,
+			Score:              score,
 		})
 	}
 
 	return report{Repos: repos}
 }
 
-func runReport(ctx context.Context, config *scanner.Config, outputFile string) error {
+func runReport(ctx context.Context, config *scanner.Config, outputFile string, includeBehind bool) error {
 	mgs, err := scanner.Scan(ctx, config)
 	if err != nil {
 		return err
 	}
 
-	r := buildReport(mgs)
+	r := buildReport(mgs, includeBehind)
 	if outputFile != "" {
 		f, err := os.Create(outputFile)
 		if err != nil {
@@ -132,14 +231,69 @@ func printReportSummary(r report) {
 	}
 	for _, repo := range r.Repos {
 		fmt.Println(repo.Path)
+		if len(repo.Reasons) > 0 {
+			details := make([]string, 0, len(repo.Reasons))
+			for _, rr := range repo.Reasons {
+				details = append(details, rr.Detail)
+			}
+			fmt.Printf("  reasons: %s\n", strings.Join(details, "; "))
+		}
+		if repo.Score.Total > 0 {
+			fmt.Printf("  risk score: %g\n", repo.Score.Total)
+		}
+		if repo.DirtySinceUnix > 0 && len(repo.Files) > 0 {
+			fmt.Printf("  dirty since: %s\n", commitAge(repo.DirtySinceUnix, time.Now()))
+		}
+		if repo.OldestUnpushedUnix > 0 {
+			fmt.Printf("  oldest unpushed: %s\n", commitAge(repo.OldestUnpushedUnix, time.Now()))
+		}
 		for _, b := range repo.Branches {
-			if b.ShownInTUI {
+			if !b.ShownInTUI {
+				continue
+			}
+			switch {
+			case b.GoneMerged:
+				fmt.Printf("  %s (upstream gone, merged into %s)\n", b.DisplayName(), b.MergedInto)
+			case b.GoneUnmerged:
+				fmt.Printf("  %s (upstream gone, %d unmerged)\n", b.DisplayName(), b.UnmergedCount)
+			case b.UpstreamGone:
+				fmt.Printf("  %s (upstream gone)\n", b.DisplayName())
+			default:
 				fmt.Printf("  %s\n", b.DisplayName())
 			}
 		}
+		for _, lc := range repo.LostCommits {
+			fmt.Printf("  lost %s %s (%s reflog)\n", shortHash(lc.Hash), lc.Subject, lc.Reflog)
+		}
+		if repo.NoRemote {
+			fmt.Printf("  no remote: %d commits, last %s\n", repo.CommitCount, commitAge(repo.LastCommitUnix, time.Now()))
+		}
+		if repo.IncomingCount > 0 {
+			fmt.Printf("  behind: %d incoming, newest %s\n", repo.IncomingCount, commitAge(repo.NewestIncomingUnix, time.Now()))
+		}
+	}
+}
+
+// commitAge describes how long ago unix was, in whole days (or "today").
+func commitAge(unix int64, now time.Time) string {
+	days := int(now.Sub(time.Unix(unix, 0)).Hours() / 24)
+	switch {
+	case days <= 0:
+		return "today"
+	case days == 1:
+		return "1 day ago"
+	default:
+		return fmt.Sprintf("%d days ago", days)
 	}
 }
 
+func shortHash(hash string) string {
+	if len(hash) <= 8 {
+		return hash
+	}
+	return hash[:8]
+}
+
 func reportCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "report",
@@ -150,13 +304,17 @@ func reportCommand() *cli.Command {
 				Aliases: []string{"o"},
 				Usage:   "Write json report to this file",
 			},
+			&cli.BoolFlag{
+				Name:  "include-behind",
+				Usage: "Also list clean repositories whose remotes have commits not yet pulled",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			config, err := loadConfig(cmd, defaultConfig)
 			if err != nil {
 				return err
 			}
-			return runReport(ctx, config, cmd.String("output-file"))
+			return runReport(ctx, config, cmd.String("output-file"), cmd.Bool("include-behind"))
 		},
 	}
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 
@@ -10,7 +11,7 @@ import (
 
 func TestBuildReportEmpty(t *testing.T) {
 	mgs := scanner.NewMultiGitStatus()
-	r := buildReport(mgs)
+	r := buildReport(mgs, false)
 	if len(r.Repos) != 0 {
 		t.Fatalf("expected 0 repos, got %d", len(r.Repos))
 	}
@@ -28,7 +29,7 @@ func TestBuildReportFileEntries(t *testing.T) {
 		},
 	})
 
-	r := buildReport(mgs)
+	r := buildReport(mgs, false)
 	if len(r.Repos) != 1 {
 		t.Fatalf("expected 1 repo, got %d", len(r.Repos))
 	}
@@ -74,7 +75,7 @@ func TestBuildReportBranchShownInTUI(t *testing.T) {
 		FilteredBranches: filtered,
 	})
 
-	r := buildReport(mgs)
+	r := buildReport(mgs, false)
 	if len(r.Repos) != 1 {
 		t.Fatalf("expected 1 repo, got %d", len(r.Repos))
 	}
@@ -118,7 +119,7 @@ func TestBuildReportIsLocalOnly(t *testing.T) {
 		FilteredBranches: []scanner.LocalBranchRef{localOnly, withRemote},
 	})
 
-	r := buildReport(mgs)
+	r := buildReport(mgs, false)
 	byName := make(map[string]reportBranchEntry)
 	for _, b := range r.Repos[0].Branches {
 		byName[b.Name] = b
@@ -138,7 +139,7 @@ func TestBuildReportSortedPaths(t *testing.T) {
 	mgs.AddResult("/a/repo", scanner.RepoStatus{Branch: "main"})
 	mgs.AddResult("/m/repo", scanner.RepoStatus{Branch: "main"})
 
-	r := buildReport(mgs)
+	r := buildReport(mgs, false)
 	if len(r.Repos) != 3 {
 		t.Fatalf("expected 3 repos, got %d", len(r.Repos))
 	}
@@ -154,7 +155,7 @@ func TestBuildReportDetachedHead(t *testing.T) {
 		Detached: true,
 	})
 
-	r := buildReport(mgs)
+	r := buildReport(mgs, false)
 	if len(r.Repos) != 1 {
 		t.Fatalf("expected 1 repo, got %d", len(r.Repos))
 	}
@@ -165,3 +166,140 @@ func TestBuildReportDetachedHead(t *testing.T) {
 		t.Errorf("CurrentBranch = %q, want abc1234", r.Repos[0].CurrentBranch)
 	}
 }
+
+func TestBuildReportLostCommits(t *testing.T) {
+	mgs := scanner.NewMultiGitStatus()
+	mgs.AddResult("/repo/e", scanner.RepoStatus{
+		Branch: "main",
+		LostCommits: []scanner.LostCommit{
+			{Hash: "0123456789abcdef", Subject: "wip", Reflog: "HEAD", ReflogUnix: 20, CommitUnix: 10},
+		},
+	})
+
+	r := buildReport(mgs, false)
+	if len(r.Repos) != 1 || len(r.Repos[0].LostCommits) != 1 {
+		t.Fatalf("expected one lost commit, got %+v", r.Repos)
+	}
+	lc := r.Repos[0].LostCommits[0]
+	if lc.Hash != "0123456789abcdef" || lc.Subject != "wip" || lc.Reflog != "HEAD" || lc.ReflogUnix != 20 || lc.CommitUnix != 10 {
+		t.Fatalf("unexpected lost commit entry: %+v", lc)
+	}
+}
+
+func TestBuildReportUpstreamGone(t *testing.T) {
+	locs := []scanner.BranchLocation{
+		{Name: "local", Exists: true, TipHash: "111"},
+		{Name: "origin", Exists: false},
+	}
+	branches := []scanner.LocalBranchRef{
+		{Name: "done", Locations: locs, UpstreamGone: true, MergedInto: "origin/main"},
+		{Name: "abandoned", Locations: locs, UpstreamGone: true, UnmergedCount: 2},
+		{Name: "new", Locations: locs},
+	}
+
+	mgs := scanner.NewMultiGitStatus()
+	mgs.AddResult("/repo/f", scanner.RepoStatus{Branch: "main", Branches: branches, FilteredBranches: branches})
+
+	byName := make(map[string]reportBranchEntry)
+	for _, b := range buildReport(mgs, false).Repos[0].Branches {
+		byName[b.Name] = b
+	}
+	if !byName["done"].GoneMerged || byName["done"].GoneUnmerged {
+		t.Errorf("done: %+v", byName["done"])
+	}
+	if byName["abandoned"].GoneMerged || !byName["abandoned"].GoneUnmerged {
+		t.Errorf("abandoned: %+v", byName["abandoned"])
+	}
+	if byName["new"].GoneMerged || byName["new"].GoneUnmerged || !byName["new"].IsLocalOnly {
+		t.Errorf("new: %+v", byName["new"])
+	}
+}
+
+func TestBuildReportIncludeBehind(t *testing.T) {
+	mgs := scanner.NewMultiGitStatus()
+	mgs.AddResult("/repo/behind", scanner.RepoStatus{
+		Branch:     "main",
+		BehindOnly: true,
+		Branches: []scanner.LocalBranchRef{{
+			Name:    "main",
+			Current: true,
+			Locations: []scanner.BranchLocation{
+				{Name: "local", Exists: true, TipHash: "aaa", TipUnix: 100},
+				{Name: "origin", Exists: true, TipHash: "bbb", TipUnix: 200, Incoming: 3},
+			},
+		}},
+	})
+
+	if r := buildReport(mgs, false); len(r.Repos) != 0 {
+		t.Fatalf("behind-only repo should be omitted by default, got %d repos", len(r.Repos))
+	}
+	r := buildReport(mgs, true)
+	if len(r.Repos) != 1 {
+		t.Fatalf("expected 1 repo with includeBehind, got %d", len(r.Repos))
+	}
+	repo := r.Repos[0]
+	if !repo.BehindOnly || repo.IncomingCount != 3 || repo.NewestIncomingUnix != 200 {
+		t.Fatalf("unexpected behind fields: %+v", repo)
+	}
+}
+
+func TestIncomingAge(t *testing.T) {
+	now := time.Unix(10*24*3600, 0)
+	for unix, want := range map[int64]string{
+		now.Unix():             "today",
+		now.Unix() - 24*3600:   "1 day ago",
+		now.Unix() - 9*24*3600: "9 days ago",
+	} {
+		if got := commitAge(unix, now); got != want {
+			t.Errorf("commitAge(%d) = %q, want %q", unix, got, want)
+		}
+	}
+}
+
+func TestBuildReportReasons(t *testing.T) {
+	mgs := scanner.NewMultiGitStatus()
+	mgs.AddResult("/repo/stashed", scanner.RepoStatus{
+		Branch: "main",
+		Reasons: []scanner.Reason{
+			{Kind: scanner.ReasonStash, Detail: "2 stash entries", Unix: 300, Rule: "stashes"},
+		},
+	})
+	r := buildReport(mgs, false)
+	if len(r.Repos) != 1 || len(r.Repos[0].Reasons) != 1 {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	got := r.Repos[0].Reasons[0]
+	if got.Kind != scanner.ReasonStash || got.SinceUnix != 300 || got.Rule != "stashes" {
+		t.Fatalf("reason = %+v", got)
+	}
+}
+
+func TestBuildReportAges(t *testing.T) {
+	mgs := scanner.NewMultiGitStatus()
+	mgs.AddResult("/repo/old", scanner.RepoStatus{
+		Branch:         "main",
+		Porcelain:      scanner.PorcelainStatus{Entries: []scanner.PorcelainEntry{{Staging: ' ', Worktree: 'M', Path: "a"}}},
+		DirtySinceUnix: 100,
+		FilteredBranches: []scanner.LocalBranchRef{
+			{Name: "main", UnpushedCount: 2, OldestUnpushedUnix: 50},
+		},
+	})
+	repo := buildReport(mgs, false).Repos[0]
+	if repo.DirtySinceUnix != 100 || repo.OldestUnpushedUnix != 50 {
+		t.Fatalf("unexpected ages: %+v", repo)
+	}
+}
+
+func TestBuildReportScore(t *testing.T) {
+	mgs := scanner.NewMultiGitStatus()
+	mgs.AddResult("/repo/risky", scanner.RepoStatus{
+		Branch: "main",
+		Score: scanner.RiskScore{Total: 6, Components: []scanner.ScoreComponent{
+			{Name: scanner.ScoreUnpushedCommits, Count: 2, Weight: 3},
+		}},
+	})
+	score := buildReport(mgs, false).Repos[0].Score
+	if score.Total != 6 || len(score.Components) != 1 || score.Components[0].Points != 6 {
+		t.Fatalf("unexpected score: %+v", score)
+	}
+}
@@ -3,6 +3,7 @@ package scanner
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"os/exec"
 	"sort"
 	"strconv"
@@ -26,9 +27,10 @@ func haveMergeBase(dir, commitA, commitB string) (bool, error) {
 
 // listLocalBranches returns all refs/heads sorted by name. When detached is false,
 // currentName is the checked-out branch name and that row has Current set.
+// Upstream and UpstreamGone come from the branch's configured tracking ref.
 func listLocalBranches(dir, currentName string, detached bool) ([]LocalBranchRef, error) {
 	out, err := runGit(dir, "for-each-ref", "refs/heads", "--sort=refname",
-		"--format=%(refname:short)\t%(objectname)\t%(committerdate:unix)")
+		"--format=%(refname:short)\t%(objectname)\t%(committerdate:unix)\t%(upstream)\t%(upstream:track,nobracket)")
 	if err != nil {
 		return nil, err
 	}
@@ -38,14 +40,18 @@ func listLocalBranches(dir, currentName string, detached bool) ([]LocalBranchRef
 	lines := strings.Split(out, "\n")
 	refs := make([]LocalBranchRef, 0, len(lines))
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
+		// Trailing upstream fields are empty for branches without tracking, and
+		// runGit trims the final line, so pad back to the five requested fields.
 		parts := strings.Split(line, "\t")
-		if len(parts) != 3 {
+		if len(parts) < 3 || len(parts) > 5 {
 			return nil, fmt.Errorf("unexpected for-each-ref line: %q", line)
 		}
+		for len(parts) < 5 {
+			parts = append(parts, "")
+		}
 		unix, err := strconv.ParseInt(parts[2], 10, 64)
 		if err != nil {
 			return nil, fmt.Errorf("parse committer date for branch %q: %w", parts[0], err)
@@ -53,10 +59,12 @@ func listLocalBranches(dir, currentName string, detached bool) ([]LocalBranchRef
 		name := parts[0]
 		cur := !detached && name == currentName
 		refs = append(refs, LocalBranchRef{
-			Name:    name,
-			TipHash: parts[1],
-			TipUnix: unix,
-			Current: cur,
+			Name:         name,
+			TipHash:      parts[1],
+			TipUnix:      unix,
+			Current:      cur,
+			Upstream:     parts[3],
+			UpstreamGone: parts[3] != "" && parts[4] == "gone",
 		})
 	}
 	return refs, nil
@@ -226,6 +234,143 @@ func computeBranchLocations(dir, branchName string, remotes []string) ([]BranchL
 	return locations, nil
 }
 
+// remoteDefaultBranchRefs resolves refs/remotes/<remote>/HEAD (set by git clone
+// or git remote set-head) to the default branch ref it points at, for each
+// remote. Remotes without that symbolic ref are skipped.
+func remoteDefaultBranchRefs(dir string, remotes []string) []string {
+	refs := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		ref, err := runGit(dir, "symbolic-ref", "--quiet", "refs/remotes/"+remote+"/HEAD")
+		if err != nil || ref == "" {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// fillDefaultBranchMerge sets MergedInto on lb by comparing it with each remote
+// default branch in defaults (full refs). UnmergedCount is only filled for
+// branches whose upstream is gone.
+func fillDefaultBranchMerge(dir string, lb *LocalBranchRef, defaults []string) error {
+	if len(defaults) == 0 {
+		return nil
+	}
+	ref := branchLocationRef("local", lb.Name)
+	for _, def := range defaults {
+		n, err := uniqueCommitCount(dir, ref, []string{def})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			lb.MergedInto = strings.TrimPrefix(def, "refs/remotes/")
+			return nil
+		}
+	}
+	if !lb.UpstreamGone {
+		return nil
+	}
+	n, err := uniqueCommitCount(dir, ref, defaults)
+	if err != nil {
+		return err
+	}
+	lb.UnmergedCount = n
+	return nil
+}
+
+// fillBranchMerge runs fillDefaultBranchMerge on the branches want accepts,
+// resolving the remote default branches only when one does. It is best-effort:
+// a failure is logged and leaves that branch unmarked.
+func fillBranchMerge(dir string, remotes []string, branches []LocalBranchRef, want func(LocalBranchRef) bool) {
+	var defaults []string
+	for i := range branches {
+		if !want(branches[i]) || branches[i].MergedInto != "" {
+			continue
+		}
+		if defaults == nil {
+			if defaults = remoteDefaultBranchRefs(dir, remotes); len(defaults) == 0 {
+				return
+			}
+		}
+		if err := fillDefaultBranchMerge(dir, &branches[i], defaults); err != nil {
+			slog.Warn("default branch merge check failed", "dir", dir, "branch", branches[i].Name, "err", err)
+		}
+	}
+}
+
+// FillMergedInto sets MergedInto on every branch but the checked-out one.
+// GitBranchStatus only does so for branches whose upstream is gone, since
+// comparing each branch with the remote default branches costs a git call per
+// branch on every scan; branch cleanup calls this when it needs the rest.
+// Failures are logged and leave the branch unmarked.
+func FillMergedInto(dir string, branches []LocalBranchRef) {
+	remotes, err := listRemotes(dir)
+	if err != nil {
+		slog.Warn("list remotes failed", "dir", dir, "err", err)
+		return
+	}
+	fillBranchMerge(dir, remotes, branches, func(lb LocalBranchRef) bool { return !lb.Current })
+}
+
+// fillUnpushedCommits sets UnpushedCount and OldestUnpushedUnix from the
+// commits on lb that no remote-tracking ref reaches.
+func fillUnpushedCommits(dir string, lb *LocalBranchRef) error {
+	out, err := runGit(dir, "log", "--format=%ct", branchLocationRef("local", lb.Name), "--not", "--remotes")
+	if err != nil || out == "" {
+		return err
+	}
+	lines := strings.Split(out, "\n")
+	// git log lists newest first, so the last line is the oldest commit.
+	oldest, err := strconv.ParseInt(lines[len(lines)-1], 10, 64)
+	if err != nil {
+		return err
+	}
+	lb.UnpushedCount = len(lines)
+	lb.OldestUnpushedUnix = oldest
+	return nil
+}
+
+// UnpushedCommit is one commit on a local branch that no remote-tracking ref reaches.
+type UnpushedCommit struct {
+	// Hash is the full object name of the commit.
+	Hash string
+	// Subject is the first line of the commit message.
+	Subject string
+	// Author is the commit author's name.
+	Author string
+	// Unix is the commit's committer date in Unix seconds.
+	Unix int64
+}
+
+// UnpushedCommits lists the commits on the local branch that no remote has
+// (git log <branch> --not --remotes), newest first. A positive limit keeps only
+// the newest limit commits; a repository without remotes would otherwise list
+// its whole history.
+func UnpushedCommits(dir, branch string, limit int) ([]UnpushedCommit, error) {
+	args := []string{"log", "--format=%H%x00%ct%x00%an%x00%s"}
+	if limit > 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+	args = append(args, branchLocationRef("local", branch), "--not", "--remotes")
+	out, err := runGit(dir, args...)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	var commits []UnpushedCommit
+	for line := range strings.SplitSeq(out, "\n") {
+		parts := strings.SplitN(line, "\x00", 4)
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("unexpected git log line: %q", line)
+		}
+		unix, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse committer date %q: %w", parts[1], err)
+		}
+		commits = append(commits, UnpushedCommit{Hash: parts[0], Subject: parts[3], Author: parts[2], Unix: unix})
+	}
+	return commits, nil
+}
+
 func tipFromLocalBranchLocation(locations []BranchLocation) (hash string, unix int64) {
 	for _, loc := range locations {
 		if loc.Name == "local" && loc.Exists {
@@ -278,6 +423,15 @@ func GitBranchStatus(dir string) (branch string, detached bool, locals []LocalBr
 		locals[i].Locations = locs
 	}
 
+	fillBranchMerge(dir, remotes, locals, func(lb LocalBranchRef) bool { return lb.UpstreamGone })
+	for i := range locals {
+		if locals[i].HasUnpushedChanges() {
+			if err = fillUnpushedCommits(dir, &locals[i]); err != nil {
+				return
+			}
+		}
+	}
+
 	if detached {
 		unix := int64(0)
 		if raw, e := runGit(dir, "log", "-1", "--format=%ct", "HEAD"); e == nil {
@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -81,3 +82,139 @@ func execGitOutput(t *testing.T, dir string, args ...string) string {
 	}
 	return string(out)
 }
+
+// gitCloneWithBareOrigin creates a bare origin with one commit on main and
+// returns a fresh clone of it (so refs/remotes/origin/HEAD is set).
+func gitCloneWithBareOrigin(t *testing.T) (clone string) {
+	t.Helper()
+	root := t.TempDir()
+	seed := filepath.Join(root, "seed")
+	gitMinimalInit(t, seed)
+	gitCommitFile(t, seed, "f.txt", "base\n", "base")
+	bare := filepath.Join(root, "origin.git")
+	execGit(t, root, "clone", "--bare", seed, bare)
+	clone = filepath.Join(root, "clone")
+	execGit(t, root, "clone", bare, clone)
+	execGit(t, clone, "config", "user.email", "t@example.com")
+	execGit(t, clone, "config", "user.name", "test")
+	return clone
+}
+
+func TestGitBranchStatusUpstreamGone(t *testing.T) {
+	dir := gitCloneWithBareOrigin(t)
+
+	// merged: pushed, merged into main, remote branch deleted
+	execGit(t, dir, "checkout", "-b", "merged")
+	gitCommitFile(t, dir, "m.txt", "m\n", "merged work")
+	execGit(t, dir, "push", "-u", "origin", "merged")
+	execGit(t, dir, "checkout", "main")
+	execGit(t, dir, "merge", "--ff-only", "merged")
+	execGit(t, dir, "push", "origin", "main")
+	execGit(t, dir, "push", "origin", "--delete", "merged")
+
+	// unmerged: pushed, remote branch deleted without merging, one more local commit
+	execGit(t, dir, "checkout", "-b", "unmerged")
+	gitCommitFile(t, dir, "u.txt", "u\n", "unmerged work")
+	execGit(t, dir, "push", "-u", "origin", "unmerged")
+	gitCommitFile(t, dir, "u.txt", "u2\n", "more unmerged work")
+	execGit(t, dir, "push", "origin", "--delete", "unmerged")
+
+	// local: never pushed, no upstream
+	execGit(t, dir, "checkout", "-b", "local")
+	gitCommitFile(t, dir, "l.txt", "l\n", "local work")
+	execGit(t, dir, "checkout", "main")
+	execGit(t, dir, "fetch", "--prune")
+
+	_, _, locals, err := GitBranchStatus(dir)
+	if err != nil {
+		t.Fatalf("GitBranchStatus: %v", err)
+	}
+	byName := make(map[string]LocalBranchRef)
+	for _, lb := range locals {
+		byName[lb.Name] = lb
+	}
+
+	merged := byName["merged"]
+	if !merged.UpstreamGone || merged.Upstream != "refs/remotes/origin/merged" || !merged.IsGoneMerged() {
+		t.Fatalf("merged branch: %+v", merged)
+	}
+	if merged.MergedInto != "origin/main" {
+		t.Fatalf("merged.MergedInto = %q, want origin/main", merged.MergedInto)
+	}
+
+	unmerged := byName["unmerged"]
+	if !unmerged.IsGoneUnmerged() || unmerged.IsGoneMerged() || unmerged.UnmergedCount != 2 {
+		t.Fatalf("unmerged branch: %+v", unmerged)
+	}
+
+	local := byName["local"]
+	if local.UpstreamGone || local.Upstream != "" || !local.IsLocalOnly() {
+		t.Fatalf("local branch: %+v", local)
+	}
+
+	main := byName["main"]
+	if main.UpstreamGone || main.Upstream != "refs/remotes/origin/main" || main.MergedInto != "" {
+		t.Fatalf("main branch: %+v", main)
+	}
+}
+
+func TestStatusForRepoBehindOnly(t *testing.T) {
+	dir := gitCloneWithBareOrigin(t)
+	other := filepath.Join(filepath.Dir(dir), "other")
+	execGit(t, filepath.Dir(dir), "clone", filepath.Join(filepath.Dir(dir), "origin.git"), other)
+	execGit(t, other, "config", "user.email", "t@example.com")
+	execGit(t, other, "config", "user.name", "test")
+	gitCommitFile(t, other, "a.txt", "a\n", "upstream one")
+	gitCommitFile(t, other, "b.txt", "b\n", "upstream two")
+	execGit(t, other, "push", "origin", "main")
+	execGit(t, dir, "fetch")
+
+	rs, include, err := StatusForRepo(&Config{}, dir)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	if include || !rs.BehindOnly {
+		t.Fatalf("include=%v BehindOnly=%v, want a behind-only repo", include, rs.BehindOnly)
+	}
+	n, newest := rs.Behind()
+	if n != 2 || newest == 0 {
+		t.Fatalf("Behind() = %d, %d; want 2 incoming with a date", n, newest)
+	}
+
+	execGit(t, dir, "pull", "--ff-only")
+	rs, _, err = StatusForRepo(&Config{}, dir)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	if rs.BehindOnly {
+		t.Fatal("BehindOnly should clear after pulling")
+	}
+}
+
+func TestUnpushedCommits(t *testing.T) {
+	dir := gitCloneWithBareOrigin(t)
+	execGit(t, dir, "checkout", "-b", "feature")
+	gitCommitFile(t, dir, "a.txt", "a\n", "first local")
+	gitCommitFile(t, dir, "b.txt", "b\n", "second local")
+
+	commits, err := UnpushedCommits(dir, "feature", 0)
+	if err != nil {
+		t.Fatalf("UnpushedCommits: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "second local" || commits[1].Subject != "first local" {
+		t.Fatalf("commits = %+v, want the two local commits newest first", commits)
+	}
+	tip := strings.TrimSpace(execGitOutput(t, dir, "rev-parse", "feature"))
+	if c := commits[0]; c.Hash != tip || c.Author != "test" || c.Unix == 0 {
+		t.Fatalf("newest = %+v, want hash %s by test", c, tip)
+	}
+
+	if commits, err = UnpushedCommits(dir, "feature", 1); err != nil || len(commits) != 1 || commits[0].Hash != tip {
+		t.Fatalf("limit 1: %+v, %v; want only the newest", commits, err)
+	}
+
+	execGit(t, dir, "push", "origin", "feature")
+	if commits, err = UnpushedCommits(dir, "feature", 0); err != nil || len(commits) != 0 {
+		t.Fatalf("after push: %+v, %v", commits, err)
+	}
+}
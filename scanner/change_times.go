@@ -0,0 +1,31 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// changeTimes returns the oldest and newest modification times, as unix
+// seconds, among the changed and untracked paths in entries. The oldest
+// approximates how long the working tree has been dirty. Deleted paths have no
+// mtime and are skipped, and an untracked directory counts with its own mtime
+// rather than its contents'. Both are 0 when no path could be read.
+func changeTimes(dir string, entries []PorcelainEntry) (oldest, newest int64, err error) {
+	for _, e := range entries {
+		fi, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(strings.TrimSuffix(e.Path, "/"))))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		t := fi.ModTime().Unix()
+		if oldest == 0 || t < oldest {
+			oldest = t
+		}
+		newest = max(newest, t)
+	}
+	return oldest, newest, nil
+}
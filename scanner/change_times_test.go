@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatusForRepoDirtySince(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "r")
+	gitMinimalInit(t, repo)
+	gitCommitFile(t, repo, "a.txt", "a\n", "one")
+	gitCommitFile(t, repo, "gone.txt", "g\n", "two")
+
+	old := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	recent := time.Now().Add(-time.Hour).Truncate(time.Second)
+	write := func(name string, mtime time.Time) {
+		t.Helper()
+		p := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("changed\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.txt", recent)
+	write("new/untracked.txt", recent)
+	if err := os.Chtimes(filepath.Join(repo, "new"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(repo, "gone.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	rs, _, err := StatusForRepo(&Config{}, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	if rs.DirtySinceUnix != old.Unix() {
+		t.Fatalf("DirtySinceUnix = %v, want the untracked directory mtime %v", time.Unix(rs.DirtySinceUnix, 0), old)
+	}
+	if len(rs.Reasons) == 0 || rs.Reasons[0].Unix != old.Unix() {
+		t.Fatalf("changes reason should start at the dirty-since time: %+v", rs.Reasons)
+	}
+}
+
+func TestRepoStatusOldestUnpushedUnix(t *testing.T) {
+	rs := RepoStatus{FilteredBranches: []LocalBranchRef{
+		{Name: "a", OldestUnpushedUnix: 300},
+		{Name: "b"},
+		{Name: "c", OldestUnpushedUnix: 200},
+	}}
+	if got := rs.OldestUnpushedUnix(); got != 200 {
+		t.Fatalf("OldestUnpushedUnix() = %d, want 200", got)
+	}
+	if got := (&RepoStatus{}).OldestUnpushedUnix(); got != 0 {
+		t.Fatalf("no branches: got %d", got)
+	}
+}
@@ -29,6 +29,28 @@ func ParseConfigFile(filename, defaultConfig string) (*Config, error) {
 	if err := compileLocalOnlyHideRegexes(&config); err != nil {
 		return nil, err
 	}
+	if err := validateNoRemoteAllowGlobs(&config); err != nil {
+		return nil, err
+	}
+	if err := validateGitignorePatterns(&config); err != nil {
+		return nil, err
+	}
+	for i, r := range config.Rules {
+		if err := validatePolicyRule(r); err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+	if err := validateRepoListColumns(&config); err != nil {
+		return nil, err
+	}
+	if err := validateScoreWeights(config.ScoreWeights()); err != nil {
+		return nil, err
+	}
+	for i, r := range config.GitIgnore.Rules {
+		if err := validateFilterRule(r); err != nil {
+			return nil, fmt.Errorf("gitignore.rules[%d]: %w", i, err)
+		}
+	}
 
 	return &config, nil
 }
@@ -52,3 +74,23 @@ func compileLocalOnlyHideRegexes(c *Config) error {
 	c.localOnlyHideCompiled = out
 	return nil
 }
+
+func validateNoRemoteAllowGlobs(c *Config) error {
+	for i, p := range c.NoRemote.Allow {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("noremote.allow[%d] %q: %w", i, p, err)
+		}
+	}
+	return nil
+}
+
+func validateGitignorePatterns(c *Config) error {
+	for i, p := range c.GitIgnore.Patterns {
+		for seg := range strings.SplitSeq(strings.TrimPrefix(p, "!"), "/") {
+			if _, err := filepath.Match(seg, ""); err != nil {
+				return fmt.Errorf("gitignore.patterns[%d] %q: %w", i, p, err)
+			}
+		}
+	}
+	return nil
+}
@@ -1,16 +1,72 @@
 package scanner
 
 import (
+	"bufio"
+	"errors"
+	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// Ignore sources, in the order their patterns apply (later sources win).
+const (
+	// IgnoreSourceConfig is the gitignore section of the dirtygit config.
+	IgnoreSourceConfig = "config"
+	// IgnoreSourceRepoFile is a .dirtygitignore file at the repository root.
+	IgnoreSourceRepoFile = ".dirtygitignore"
+	// IgnoreSourceGitConfig is the repository's dirtygit.ignore* git config values.
+	IgnoreSourceGitConfig = "git config"
 )
 
+// IgnoreSourceCount is how many porcelain entries one ignore source hid.
+type IgnoreSourceCount struct {
+	Source string
+	Count  int
+}
+
+// ignoreLayer is one source's gitignore patterns.
+type ignoreLayer struct {
+	source   string
+	patterns []gitignore.Pattern
+}
+
+// Excluder hides porcelain entries that match the config's gitignore section:
+// basename globs (files), directory component globs (dirs), and gitignore
+// patterns. Patterns are applied after the globs, as later lines in a
+// .gitignore would be, so a negated pattern can re-include a glob match.
+// [Excluder.ForRepo] layers per-repository patterns on top. Status-aware
+// rules (see [Excluder.WithRules]) additionally hide entries by path and status.
 type Excluder struct {
-	files []string
-	dirs  []string
+	files  []string
+	dirs   []string
+	layers []ignoreLayer
+	rules  []filterRule
+	// modeOnly is filled by ForRepo when a rule needs it.
+	modeOnly modeOnlyChanges
 }
 
 func (e Excluder) IsExcluded(path string) bool {
+	_, excluded := e.excludedBy(path)
+	return excluded
+}
+
+// excludedBy reports whether path is excluded and which source decided it.
+func (e Excluder) excludedBy(path string) (source string, excluded bool) {
+	// Untracked directories appear in porcelain output with a trailing slash.
+	isDir := strings.HasSuffix(path, "/")
+	if src, r := e.matchPatterns(strings.TrimSuffix(path, "/"), isDir); r != gitignore.NoMatch {
+		return src, r == gitignore.Exclude
+	}
+	return IgnoreSourceConfig, e.matchGlobs(path)
+}
+
+// matchGlobs applies the legacy fileglob/dirglob rules: filepath.Match on the
+// basename and on each directory component.
+func (e Excluder) matchGlobs(path string) bool {
 	dir, base := filepath.Split(path)
 	dirs := strings.Split(filepath.ToSlash(dir), "/")
 	for _, pattern := range e.files {
@@ -30,20 +86,155 @@ func (e Excluder) IsExcluded(path string) bool {
 	return false
 }
 
+// matchPatterns returns the source and result of the last gitignore pattern
+// that matches path (repo-relative, slash-separated), or NoMatch.
+func (e Excluder) matchPatterns(path string, isDir bool) (string, gitignore.MatchResult) {
+	if len(e.layers) == 0 {
+		return "", gitignore.NoMatch
+	}
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for l := len(e.layers) - 1; l >= 0; l-- {
+		layer := e.layers[l]
+		for i := len(layer.patterns) - 1; i >= 0; i-- {
+			if r := layer.patterns[i].Match(parts, isDir); r != gitignore.NoMatch {
+				return layer.source, r
+			}
+		}
+	}
+	return "", gitignore.NoMatch
+}
+
 func (e Excluder) FilterPorcelainStatus(st PorcelainStatus) PorcelainStatus {
+	filtered, _ := e.FilterPorcelainStatusCounted(st)
+	return filtered
+}
+
+// FilterPorcelainStatusCounted is [Excluder.FilterPorcelainStatus] that also
+// reports how many entries each source hid, in source order, omitting zeros.
+func (e Excluder) FilterPorcelainStatusCounted(st PorcelainStatus) (PorcelainStatus, []IgnoreSourceCount) {
 	filtered := PorcelainStatus{Entries: make([]PorcelainEntry, 0, len(st.Entries))}
+	hidden := make(map[string]int)
 	for _, entry := range st.Entries {
-		if e.IsExcluded(entry.Path) {
+		if src, excluded := e.excludedBy(entry.Path); excluded {
+			hidden[src]++
+			continue
+		}
+		if e.ruleExcludes(entry) {
+			hidden[IgnoreSourceConfig]++
 			continue
 		}
 		filtered.Entries = append(filtered.Entries, entry)
 	}
-	return filtered
+	var counts []IgnoreSourceCount
+	for _, src := range []string{IgnoreSourceConfig, IgnoreSourceRepoFile, IgnoreSourceGitConfig} {
+		if n := hidden[src]; n > 0 {
+			counts = append(counts, IgnoreSourceCount{Source: src, Count: n})
+		}
+	}
+	return filtered, counts
+}
+
+// WithRules returns a copy of e that also hides entries matching any rule.
+func (e Excluder) WithRules(rules []FilterRule) Excluder {
+	e.rules = compileFilterRules(rules)
+	return e
+}
+
+// ruleExcludes reports whether any status-aware rule matches entry.
+func (e Excluder) ruleExcludes(entry PorcelainEntry) bool {
+	for _, r := range e.rules {
+		if r.matches(entry, e.modeOnly.isModeOnly) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForRepo returns a copy of e with the repository's own patterns layered on
+// top: the .dirtygitignore file at its root, then dirtygit.ignore* git config
+// values. Missing sources are skipped. When a rule matches mode-only changes,
+// ForRepo also asks git which paths changed only their file mode.
+func (e Excluder) ForRepo(dir string) (Excluder, error) {
+	fileLines, err := readIgnoreFile(filepath.Join(dir, IgnoreSourceRepoFile))
+	if err != nil {
+		return e, err
+	}
+	configLines, err := gitConfigIgnorePatterns(dir)
+	if err != nil {
+		return e, err
+	}
+	out := e
+	out.layers = append([]ignoreLayer(nil), e.layers...)
+	if len(fileLines) > 0 {
+		out.layers = append(out.layers, ignoreLayer{source: IgnoreSourceRepoFile, patterns: parseGitignorePatterns(fileLines)})
+	}
+	if len(configLines) > 0 {
+		out.layers = append(out.layers, ignoreLayer{source: IgnoreSourceGitConfig, patterns: parseGitignorePatterns(configLines)})
+	}
+	if slices.ContainsFunc(e.rules, func(r filterRule) bool { return r.modeOnly }) {
+		out.modeOnly, err = findModeOnlyChanges(dir)
+		if err != nil {
+			return e, err
+		}
+	}
+	return out, nil
+}
+
+// readIgnoreFile returns the lines of a gitignore-style file; a missing file yields none.
+func readIgnoreFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimSuffix(sc.Text(), "\r"))
+	}
+	return lines, sc.Err()
+}
+
+// gitConfigIgnorePatterns returns the values of every dirtygit.ignore* key
+// (e.g. dirtygit.ignore, dirtygit.ignorePattern) visible in dir.
+func gitConfigIgnorePatterns(dir string) ([]string, error) {
+	out, err := runGit(dir, "config", "--get-regexp", `^dirtygit\.ignore`)
+	if err != nil {
+		// git config exits 1 when no key matches.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var values []string
+	for line := range strings.SplitSeq(out, "\n") {
+		if _, v, ok := strings.Cut(line, " "); ok {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// parseGitignorePatterns parses gitignore lines, skipping blanks and comments.
+func parseGitignorePatterns(lines []string) []gitignore.Pattern {
+	out := make([]gitignore.Pattern, 0, len(lines))
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		out = append(out, gitignore.ParsePattern(l, nil))
+	}
+	return out
 }
 
-func NewExcluder(files, dirs []string) Excluder {
+func NewExcluder(files, dirs, patterns []string) Excluder {
 	return Excluder{
-		files: files,
-		dirs:  dirs,
+		files:  files,
+		dirs:   dirs,
+		layers: []ignoreLayer{{source: IgnoreSourceConfig, patterns: parseGitignorePatterns(patterns)}},
 	}
 }
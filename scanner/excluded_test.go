@@ -1,6 +1,9 @@
 package scanner
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -53,3 +56,87 @@ func TestExcluderFilterPorcelainStatus(t *testing.T) {
 		t.Fatalf("filtered path = %q, want cmd/app/main.go", got.Entries[0].Path)
 	}
 }
+
+func TestExcluderGitignorePatterns(t *testing.T) {
+	ex := NewExcluder([]string{"*.log"}, []string{"vendor"}, []string{
+		"# comment",
+		"/build",
+		"docs/**/*.tmp",
+		"out/",
+		"gen/pb",
+		"!keep.log",
+	})
+
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{path: "build/app", want: true},
+		{path: "src/build/app", want: false},
+		{path: "docs/a/b/c.tmp", want: true},
+		{path: "docs/c.tmp", want: true},
+		{path: "src/docs/c.tmp", want: false},
+		{path: "out/", want: true},
+		{path: "web/out/bundle.js", want: true},
+		{path: "out", want: false},
+		{path: "gen/pb/x.go", want: true},
+		{path: "src/gen/pb/x.go", want: false},
+		{path: "app/debug.log", want: true},
+		{path: "app/keep.log", want: false},
+		{path: "vendor/", want: true},
+		{path: "vendor/pkg/a.go", want: true},
+		{path: "main.go", want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := ex.IsExcluded(tc.path); got != tc.want {
+				t.Fatalf("IsExcluded(%q) = %v, want %v", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExcluderForRepoCountsSources(t *testing.T) {
+	repo := t.TempDir()
+	gitMinimalInit(t, repo)
+	content := "gen/*.pb.go\n# local only\n.env.local\n!keep.log\n"
+	if err := os.WriteFile(filepath.Join(repo, ".dirtygitignore"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	execGit(t, repo, "config", "--add", "dirtygit.ignore", "*.bak")
+	execGit(t, repo, "config", "--add", "dirtygit.ignorePattern", "scratch/")
+
+	ex, err := NewExcluder([]string{"*.log"}, nil, nil).ForRepo(repo)
+	if err != nil {
+		t.Fatalf("ForRepo: %v", err)
+	}
+	in := PorcelainStatus{Entries: []PorcelainEntry{
+		{Staging: '?', Worktree: '?', Path: "main.go"},
+		{Staging: '?', Worktree: '?', Path: "debug.log"},
+		{Staging: '?', Worktree: '?', Path: "keep.log"},
+		{Staging: '?', Worktree: '?', Path: "gen/api.pb.go"},
+		{Staging: '?', Worktree: '?', Path: ".env.local"},
+		{Staging: '?', Worktree: '?', Path: "old.bak"},
+		{Staging: '?', Worktree: '?', Path: "scratch/"},
+	}}
+	got, hidden := ex.FilterPorcelainStatusCounted(in)
+	if len(got.Entries) != 2 || got.Entries[0].Path != "main.go" || got.Entries[1].Path != "keep.log" {
+		t.Fatalf("kept entries = %+v, want main.go and keep.log", got.Entries)
+	}
+	want := []IgnoreSourceCount{
+		{Source: IgnoreSourceConfig, Count: 1},
+		{Source: IgnoreSourceRepoFile, Count: 2},
+		{Source: IgnoreSourceGitConfig, Count: 2},
+	}
+	if !reflect.DeepEqual(hidden, want) {
+		t.Fatalf("hidden = %+v, want %+v", hidden, want)
+	}
+
+	plain, err := NewExcluder(nil, nil, nil).ForRepo(t.TempDir())
+	if err != nil {
+		t.Fatalf("ForRepo without sources: %v", err)
+	}
+	if _, hidden := plain.FilterPorcelainStatusCounted(in); len(hidden) != 0 {
+		t.Fatalf("hidden = %+v, want none", hidden)
+	}
+}
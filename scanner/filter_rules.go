@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// FilterRule hides porcelain entries by path and status together, e.g. only
+// untracked *.log files, or changes that only flip the file mode.
+type FilterRule struct {
+	// Path is a .gitignore-style pattern; empty matches every path.
+	Path string `yaml:"path"`
+	// Status lists two-character porcelain codes (staging then worktree, as in
+	// git status --short, e.g. "??", " M", "D "). "*" matches any code in that
+	// column and "." is the same as " " (unmodified). Empty matches any status.
+	Status []string `yaml:"status"`
+	// ModeOnly restricts the rule to entries whose only change is the file mode
+	// (e.g. chmod +x with identical content).
+	ModeOnly bool `yaml:"modeonly"`
+}
+
+// filterRule is a [FilterRule] with its path pattern parsed.
+type filterRule struct {
+	path     gitignore.Pattern
+	status   []string
+	modeOnly bool
+}
+
+func compileFilterRules(rules []FilterRule) []filterRule {
+	out := make([]filterRule, 0, len(rules))
+	for _, r := range rules {
+		fr := filterRule{status: r.Status, modeOnly: r.ModeOnly}
+		if r.Path != "" {
+			fr.path = gitignore.ParsePattern(r.Path, nil)
+		}
+		out = append(out, fr)
+	}
+	return out
+}
+
+// validateFilterRule checks the path pattern and status codes of one rule.
+func validateFilterRule(r FilterRule) error {
+	if strings.HasPrefix(r.Path, "!") {
+		return fmt.Errorf("path %q: negation is not supported in rules", r.Path)
+	}
+	for seg := range strings.SplitSeq(r.Path, "/") {
+		if _, err := filepath.Match(seg, ""); err != nil {
+			return fmt.Errorf("path %q: %w", r.Path, err)
+		}
+	}
+	for _, s := range r.Status {
+		if len(s) != 2 || !strings.ContainsRune(statusCodeChars, rune(s[0])) ||
+			!strings.ContainsRune(statusCodeChars, rune(s[1])) {
+			return fmt.Errorf("status %q: want two status characters such as \"??\" or \" M\"", s)
+		}
+	}
+	return nil
+}
+
+// statusCodeChars are the characters allowed in a rule status code.
+const statusCodeChars = " .*MTADRCU?!"
+
+// statusColumnMatches compares one column of a rule status with an entry code.
+func statusColumnMatches(want byte, got git.StatusCode) bool {
+	switch want {
+	case '*':
+		return true
+	case '.':
+		return got == git.Unmodified
+	}
+	return git.StatusCode(want) == got
+}
+
+// matches reports whether the rule hides entry. modeOnly says whether the
+// entry's changes are file-mode-only; it is only consulted for ModeOnly rules.
+func (r filterRule) matches(entry PorcelainEntry, modeOnly func(PorcelainEntry) bool) bool {
+	if r.path != nil {
+		path := entry.Path
+		isDir := strings.HasSuffix(path, "/")
+		parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
+		if r.path.Match(parts, isDir) != gitignore.Exclude {
+			return false
+		}
+	}
+	if len(r.status) > 0 {
+		ok := false
+		for _, s := range r.status {
+			if statusColumnMatches(s[0], entry.Staging) && statusColumnMatches(s[1], entry.Worktree) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return !r.modeOnly || modeOnly(entry)
+}
+
+// modeOnlyChanges records which paths differ only in file mode, separately for
+// the index (staged) and the working tree.
+type modeOnlyChanges struct {
+	staged   map[string]bool
+	worktree map[string]bool
+}
+
+// isModeOnly reports whether every changed column of entry is a mode-only change.
+func (c modeOnlyChanges) isModeOnly(entry PorcelainEntry) bool {
+	if entry.Staging == git.Unmodified && entry.Worktree == git.Unmodified {
+		return false
+	}
+	if entry.Staging != git.Unmodified && !c.staged[entry.Path] {
+		return false
+	}
+	if entry.Worktree != git.Unmodified && !c.worktree[entry.Path] {
+		return false
+	}
+	return true
+}
+
+// findModeOnlyChanges runs git diff (and git diff --cached) with --raw and
+// --numstat; a path is mode-only when the modes differ and no lines changed.
+func findModeOnlyChanges(dir string) (modeOnlyChanges, error) {
+	staged, err := modeOnlyPaths(dir, "--cached")
+	if err != nil {
+		return modeOnlyChanges{}, err
+	}
+	worktree, err := modeOnlyPaths(dir)
+	if err != nil {
+		return modeOnlyChanges{}, err
+	}
+	return modeOnlyChanges{staged: staged, worktree: worktree}, nil
+}
+
+func modeOnlyPaths(dir string, extra ...string) (map[string]bool, error) {
+	args := append([]string{"-c", "core.quotePath=false", "diff", "--raw", "--numstat", "--no-renames"}, extra...)
+	out, err := runGit(dir, args...)
+	if err != nil {
+		return nil, err
+	}
+	modeChanged := make(map[string]bool)
+	noLines := make(map[string]bool)
+	for line := range strings.SplitSeq(out, "\n") {
+		if strings.HasPrefix(line, ":") {
+			meta, path, ok := strings.Cut(line, "\t")
+			f := strings.Fields(meta)
+			if ok && len(f) >= 2 && f[0][1:] != f[1] {
+				modeChanged[path] = true
+			}
+			continue
+		}
+		f := strings.SplitN(line, "\t", 3)
+		if len(f) == 3 && f[0] == "0" && f[1] == "0" {
+			noLines[f[2]] = true
+		}
+	}
+	paths := make(map[string]bool)
+	for p := range modeChanged {
+		if noLines[p] {
+			paths[p] = true
+		}
+	}
+	return paths, nil
+}
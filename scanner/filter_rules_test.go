@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExcluderRulesMatchStatus(t *testing.T) {
+	ex := NewExcluder(nil, nil, nil).WithRules([]FilterRule{
+		{Path: "*.log", Status: []string{"??"}},
+		{Path: "tmp/", Status: []string{"*D"}},
+		{Status: []string{"A."}},
+	})
+	in := PorcelainStatus{Entries: []PorcelainEntry{
+		{Staging: '?', Worktree: '?', Path: "debug.log"},
+		{Staging: ' ', Worktree: 'M', Path: "tracked.log"},
+		{Staging: 'M', Worktree: 'D', Path: "tmp/x"},
+		{Staging: ' ', Worktree: 'M', Path: "tmp/y"},
+		{Staging: 'A', Worktree: ' ', Path: "new.go"},
+		{Staging: 'A', Worktree: 'M', Path: "newer.go"},
+	}}
+	got, hidden := ex.FilterPorcelainStatusCounted(in)
+	var kept []string
+	for _, e := range got.Entries {
+		kept = append(kept, e.Path)
+	}
+	want := []string{"tracked.log", "tmp/y", "newer.go"}
+	if len(kept) != len(want) {
+		t.Fatalf("kept = %v, want %v", kept, want)
+	}
+	for i := range want {
+		if kept[i] != want[i] {
+			t.Fatalf("kept = %v, want %v", kept, want)
+		}
+	}
+	if len(hidden) != 1 || hidden[0].Source != IgnoreSourceConfig || hidden[0].Count != 3 {
+		t.Fatalf("hidden = %+v, want 3 from config", hidden)
+	}
+}
+
+func TestExcluderRulesModeOnly(t *testing.T) {
+	repo := t.TempDir()
+	gitMinimalInit(t, repo)
+	execGit(t, repo, "config", "core.fileMode", "true")
+	gitCommitFile(t, repo, "run.sh", "echo hi\n", "script")
+	gitCommitFile(t, repo, "edit.sh", "echo a\n", "edit")
+	if err := os.Chmod(filepath.Join(repo, "run.sh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "edit.sh"), []byte("echo b\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{}
+	cfg.GitIgnore.Rules = []FilterRule{{ModeOnly: true}}
+	rs, _, err := StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	if len(rs.Porcelain.Entries) != 1 || rs.Porcelain.Entries[0].Path != "edit.sh" {
+		t.Fatalf("entries = %+v, want only edit.sh (content change)", rs.Porcelain.Entries)
+	}
+}
+
+func TestParseConfigFileInvalidFilterRule(t *testing.T) {
+	for name, content := range map[string]string{
+		"status":   "gitignore:\n  rules:\n    - path: \"*.log\"\n      status: [\"?\"]\n",
+		"negation": "gitignore:\n  rules:\n    - path: \"!keep.log\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfgPath := filepath.Join(t.TempDir(), "rules.yml")
+			if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ParseConfigFile(cfgPath, ""); err == nil {
+				t.Fatal("ParseConfigFile() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LostCommit is a commit recorded in a reflog (HEAD or refs/heads/*) that no
+// local branch, tag, or remote-tracking ref can reach. These are typically left
+// behind by committing on a detached HEAD or resetting a branch backwards.
+type LostCommit struct {
+	// Hash is the full object name of the commit.
+	Hash string
+	// Subject is the first line of the commit message.
+	Subject string
+	// Reflog is the short name of the reflog the commit was found in (e.g. "HEAD", "main").
+	Reflog string
+	// ReflogUnix is when the newest reflog entry for this commit was recorded (Unix seconds).
+	ReflogUnix int64
+	// CommitUnix is the commit's committer date in Unix seconds.
+	CommitUnix int64
+}
+
+// reflogEntry is one parsed line of git log --walk-reflogs.
+type reflogEntry struct {
+	hash       string
+	reflog     string
+	reflogUnix int64
+	commitUnix int64
+	subject    string
+}
+
+// parseReflogLines parses git log -g --date=unix output in the
+// "%H\t%gd\t%ct\t%s" format. %gd looks like "HEAD@{1700000000}".
+func parseReflogLines(out string) ([]reflogEntry, error) {
+	if out == "" {
+		return nil, nil
+	}
+	var entries []reflogEntry
+	for line := range strings.SplitSeq(out, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "\t", 4)
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("unexpected reflog line: %q", line)
+		}
+		selector := parts[1]
+		at := strings.LastIndex(selector, "@{")
+		if at < 0 || !strings.HasSuffix(selector, "}") {
+			return nil, fmt.Errorf("unexpected reflog selector: %q", selector)
+		}
+		reflogUnix, err := strconv.ParseInt(selector[at+2:len(selector)-1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse reflog date %q: %w", selector, err)
+		}
+		commitUnix, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse committer date %q: %w", parts[2], err)
+		}
+		e := reflogEntry{
+			hash:       parts[0],
+			reflog:     selector[:at],
+			reflogUnix: reflogUnix,
+			commitUnix: commitUnix,
+		}
+		if len(parts) == 4 {
+			e.subject = parts[3]
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// reflogEntries returns reflog entries for ref; a ref without a reflog yields none.
+func reflogEntries(dir, ref string) ([]reflogEntry, error) {
+	out, err := runGit(dir, "log", "--walk-reflogs", "--date=unix",
+		"--format=%H%x09%gd%x09%ct%x09%s", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	return parseReflogLines(out)
+}
+
+// unreachableCommits filters hashes down to those not reachable from any local
+// branch, tag, or remote-tracking ref.
+func unreachableCommits(dir string, hashes []string) (map[string]bool, error) {
+	out := make(map[string]bool)
+	if len(hashes) == 0 {
+		return out, nil
+	}
+	cmd := exec.Command("git", "rev-list", "--stdin", "--not", "--branches", "--tags", "--remotes")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(strings.Join(hashes, "\n") + "\n")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: git rev-list: %w", dir, err)
+	}
+	want := make(map[string]bool, len(hashes))
+	for _, h := range hashes {
+		want[h] = true
+	}
+	for line := range strings.SplitSeq(stdout.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if want[line] {
+			out[line] = true
+		}
+	}
+	return out, nil
+}
+
+// FindLostCommits lists commits from the HEAD and local branch reflogs whose
+// reflog entries are newer than maxAge (relative to now) and that no branch,
+// tag, or remote-tracking ref can reach. Results are newest reflog entry first.
+func FindLostCommits(dir string, maxAge time.Duration, now time.Time) ([]LostCommit, error) {
+	if maxAge <= 0 {
+		return nil, nil
+	}
+	refs := []string{"HEAD"}
+	branches, err := runGit(dir, "for-each-ref", "refs/heads", "--format=%(refname)")
+	if err != nil {
+		return nil, err
+	}
+	if branches != "" {
+		refs = append(refs, strings.Split(branches, "\n")...)
+	}
+
+	cutoff := now.Add(-maxAge).Unix()
+	byHash := make(map[string]LostCommit)
+	for _, ref := range refs {
+		entries, err := reflogEntries(dir, ref)
+		if err != nil {
+			// HEAD of an empty repository has no commits to walk.
+			if ref == "HEAD" {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.reflogUnix < cutoff {
+				continue
+			}
+			if prev, ok := byHash[e.hash]; ok && prev.ReflogUnix >= e.reflogUnix {
+				continue
+			}
+			byHash[e.hash] = LostCommit{
+				Hash:       e.hash,
+				Subject:    e.subject,
+				Reflog:     e.reflog,
+				ReflogUnix: e.reflogUnix,
+				CommitUnix: e.commitUnix,
+			}
+		}
+	}
+
+	hashes := make([]string, 0, len(byHash))
+	for h := range byHash {
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes)
+	unreachable, err := unreachableCommits(dir, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	lost := make([]LostCommit, 0, len(unreachable))
+	for _, h := range hashes {
+		if unreachable[h] {
+			lost = append(lost, byHash[h])
+		}
+	}
+	sort.SliceStable(lost, func(i, j int) bool {
+		if lost[i].ReflogUnix != lost[j].ReflogUnix {
+			return lost[i].ReflogUnix > lost[j].ReflogUnix
+		}
+		return lost[i].CommitUnix > lost[j].CommitUnix
+	})
+	return lost, nil
+}
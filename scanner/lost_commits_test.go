@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReflogLines(t *testing.T) {
+	out := "aaa\tHEAD@{1700000100}\t1700000000\tfix: thing\twith tab\n" +
+		"bbb\tfeature/x@{1700000050}\t1699999999\t\n"
+	got, err := parseReflogLines(out)
+	if err != nil {
+		t.Fatalf("parseReflogLines: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("entries = %d, want 2", len(got))
+	}
+	if got[0].hash != "aaa" || got[0].reflog != "HEAD" || got[0].reflogUnix != 1700000100 ||
+		got[0].commitUnix != 1700000000 || got[0].subject != "fix: thing\twith tab" {
+		t.Fatalf("unexpected first entry: %+v", got[0])
+	}
+	if got[1].reflog != "feature/x" || got[1].reflogUnix != 1700000050 {
+		t.Fatalf("unexpected second entry: %+v", got[1])
+	}
+
+	if _, err := parseReflogLines("aaa\tHEAD\t1\tx"); err == nil {
+		t.Fatal("expected error for selector without @{...}")
+	}
+}
+
+func TestFindLostCommitsDetachedAndReset(t *testing.T) {
+	dir := t.TempDir()
+	gitMinimalInit(t, dir)
+	gitCommitFile(t, dir, "f.txt", "1\n", "base")
+	gitCommitFile(t, dir, "f.txt", "2\n", "reset-away")
+	resetAway := strings.TrimSpace(execGitOutput(t, dir, "rev-parse", "HEAD"))
+	execGit(t, dir, "reset", "--hard", "HEAD~1")
+	execGit(t, dir, "checkout", "--detach")
+	gitCommitFile(t, dir, "f.txt", "3\n", "on-detached")
+	detached := strings.TrimSpace(execGitOutput(t, dir, "rev-parse", "HEAD"))
+	execGit(t, dir, "checkout", "main")
+
+	lost, err := FindLostCommits(dir, time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("FindLostCommits: %v", err)
+	}
+	found := make(map[string]LostCommit)
+	for _, lc := range lost {
+		found[lc.Hash] = lc
+	}
+	if len(found) != 2 {
+		t.Fatalf("lost = %+v, want 2 commits", lost)
+	}
+	if found[resetAway].Subject != "reset-away" {
+		t.Fatalf("missing reset-away commit: %+v", lost)
+	}
+	if found[detached].Subject != "on-detached" || found[detached].Reflog != "HEAD" {
+		t.Fatalf("missing detached commit: %+v", lost)
+	}
+
+	// A rescue branch makes the detached commit reachable again.
+	execGit(t, dir, "branch", "rescue", detached)
+	lost, err = FindLostCommits(dir, time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("FindLostCommits after rescue: %v", err)
+	}
+	if len(lost) != 1 || lost[0].Hash != resetAway {
+		t.Fatalf("after rescue lost = %+v, want only %s", lost, resetAway)
+	}
+
+	// Entries older than the window are ignored.
+	lost, err = FindLostCommits(dir, time.Hour, time.Now().Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("FindLostCommits outside window: %v", err)
+	}
+	if len(lost) != 0 {
+		t.Fatalf("outside window lost = %+v, want none", lost)
+	}
+}
+
+func TestFindLostCommitsDisabledAndEmptyRepo(t *testing.T) {
+	dir := t.TempDir()
+	gitMinimalInit(t, dir)
+	lost, err := FindLostCommits(dir, time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("FindLostCommits on empty repo: %v", err)
+	}
+	if len(lost) != 0 {
+		t.Fatalf("empty repo lost = %+v", lost)
+	}
+	lost, err = FindLostCommits(dir, 0, time.Now())
+	if err != nil || lost != nil {
+		t.Fatalf("maxage 0 should disable, got %+v, %v", lost, err)
+	}
+}
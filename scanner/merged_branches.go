@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RepoMergedBranches lists the local branches in one repository that are safe
+// to delete because a remote default branch already contains them.
+type RepoMergedBranches struct {
+	Path     string
+	Branches []LocalBranchRef
+}
+
+// MergedBranches returns local branches fully merged into a remote default
+// branch (see [LocalBranchRef.MergedInto]; call [FillMergedInto] on
+// rs.Branches first for branches whose upstream still exists). The checked-out branch, branches
+// named in branches.default, and local copies of a remote default branch
+// itself (e.g. "main" when merged into "origin/main") are never candidates.
+func (rs *RepoStatus) MergedBranches(c *Config) []LocalBranchRef {
+	var out []LocalBranchRef
+	for _, lb := range rs.Branches {
+		if lb.Current || lb.MergedInto == "" {
+			continue
+		}
+		if c != nil && slices.Contains(c.Branches.Default, lb.Name) {
+			continue
+		}
+		if _, def, ok := strings.Cut(lb.MergedInto, "/"); ok && def == lb.Name {
+			continue
+		}
+		out = append(out, lb)
+	}
+	return out
+}
+
+// ScanMergedBranches walks every repository under the configured scan roots,
+// clean or dirty, and returns those with merged branches, sorted by path.
+func ScanMergedBranches(ctx context.Context, config *Config) ([]RepoMergedBranches, error) {
+	repositories := make(chan string, 1000)
+	walkErr := make(chan error, 1)
+	go func() {
+		walkErr <- Walk(ctx, config, repositories, nil)
+	}()
+
+	var (
+		mu  sync.Mutex
+		out []RepoMergedBranches
+		eg  errgroup.Group
+	)
+	for d := range repositories {
+		eg.Go(func() error {
+			branch, detached, branches, err := GitBranchStatus(d)
+			if err != nil {
+				// Best-effort, as in the main scan: skip repos whose branches can't be read.
+				slog.Warn("branch status scan failed", "dir", d, "err", err)
+				return nil
+			}
+			FillMergedInto(d, branches)
+			rs := RepoStatus{Branch: branch, Detached: detached, Branches: branches}
+			if merged := rs.MergedBranches(config); len(merged) > 0 {
+				mu.Lock()
+				out = append(out, RepoMergedBranches{Path: d, Branches: merged})
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	err := eg.Wait()
+	if werr := <-walkErr; err == nil {
+		err = werr
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+// DeleteMergedBranch deletes a local branch with `git branch -d`, so git
+// itself refuses when the branch is not merged into its upstream or HEAD.
+func DeleteMergedBranch(dir, name string) error {
+	cmd := exec.Command("git", "branch", "-d", "--", name)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: git branch -d %s: %w: %s", dir, name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
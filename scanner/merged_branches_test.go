@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestMergedBranchesAndDelete(t *testing.T) {
+	dir := gitCloneWithBareOrigin(t)
+
+	// done: merged into origin/main while its own remote branch still exists
+	execGit(t, dir, "checkout", "-b", "done")
+	gitCommitFile(t, dir, "d.txt", "d\n", "done work")
+	execGit(t, dir, "push", "-u", "origin", "done")
+	execGit(t, dir, "checkout", "main")
+	execGit(t, dir, "merge", "--ff-only", "done")
+	execGit(t, dir, "push", "origin", "main")
+
+	// wip: has a commit origin/main lacks
+	execGit(t, dir, "checkout", "-b", "wip")
+	gitCommitFile(t, dir, "w.txt", "w\n", "wip work")
+	execGit(t, dir, "checkout", "main")
+
+	// keep: merged but listed in branches.default
+	execGit(t, dir, "branch", "keep")
+
+	branch, detached, branches, err := GitBranchStatus(dir)
+	if err != nil {
+		t.Fatalf("GitBranchStatus: %v", err)
+	}
+	rs := RepoStatus{Branch: branch, Detached: detached, Branches: branches}
+	cfg := &Config{}
+	if merged := rs.MergedBranches(cfg); len(merged) != 0 {
+		t.Fatalf("MergedBranches before FillMergedInto = %+v, want none", merged)
+	}
+	FillMergedInto(dir, rs.Branches)
+	cfg.Branches.Default = []string{"keep"}
+	merged := rs.MergedBranches(cfg)
+	if len(merged) != 1 || merged[0].Name != "done" || merged[0].MergedInto != "origin/main" {
+		t.Fatalf("MergedBranches = %+v, want only done merged into origin/main", merged)
+	}
+
+	cfg.ScanDirs.Include = []string{filepath.Dir(dir)}
+	repos, err := ScanMergedBranches(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("ScanMergedBranches: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Path != dir || len(repos[0].Branches) != 1 {
+		t.Fatalf("ScanMergedBranches = %+v, want the clone with one branch", repos)
+	}
+
+	if err := DeleteMergedBranch(dir, "done"); err != nil {
+		t.Fatalf("DeleteMergedBranch: %v", err)
+	}
+	if out := execGitOutput(t, dir, "branch", "--list", "done"); out != "" {
+		t.Fatalf("done still exists: %q", out)
+	}
+	if err := DeleteMergedBranch(dir, "wip"); err == nil {
+		t.Fatal("git branch -d should refuse the unmerged wip branch")
+	}
+}
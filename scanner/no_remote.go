@@ -0,0 +1,44 @@
+package scanner
+
+import (
+	"strconv"
+)
+
+// localCommitSummary counts commits reachable from local branches and returns
+// the newest committer date among their tips. An empty repository yields zeros.
+func localCommitSummary(dir string) (count int, lastUnix int64, err error) {
+	out, err := runGit(dir, "rev-list", "--count", "--branches")
+	if err != nil {
+		return 0, 0, err
+	}
+	count, err = strconv.Atoi(out)
+	if err != nil || count == 0 {
+		return count, 0, err
+	}
+	out, err = runGit(dir, "log", "-1", "--format=%ct", "--branches")
+	if err != nil {
+		return 0, 0, err
+	}
+	lastUnix, err = strconv.ParseInt(out, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return count, lastUnix, nil
+}
+
+// fillNoRemote sets NoRemote, CommitCount, and LastCommitUnix when dir has no
+// configured remotes but does have commits.
+func fillNoRemote(dir string, rs *RepoStatus) error {
+	remotes, err := listRemotes(dir)
+	if err != nil || len(remotes) > 0 {
+		return err
+	}
+	count, last, err := localCommitSummary(dir)
+	if err != nil || count == 0 {
+		return err
+	}
+	rs.NoRemote = true
+	rs.CommitCount = count
+	rs.LastCommitUnix = last
+	return nil
+}
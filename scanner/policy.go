@@ -0,0 +1,198 @@
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reason kinds: the conditions that can make a repository dirty.
+const (
+	// ReasonChanges is uncommitted working-tree or index changes.
+	ReasonChanges = "changes"
+	// ReasonUnpushed is one local branch with commits no remote has.
+	ReasonUnpushed = "unpushed"
+	// ReasonStash is one or more stash entries. Ignored unless a rule includes it.
+	ReasonStash = "stash"
+	// ReasonLostCommits is reflog commits no ref can reach.
+	ReasonLostCommits = "lost-commits"
+	// ReasonNoRemote is commits with no remote configured (see noremote.include).
+	ReasonNoRemote = "no-remote"
+)
+
+// reasonKinds lists every reason kind in report order.
+var reasonKinds = []string{ReasonChanges, ReasonUnpushed, ReasonStash, ReasonLostCommits, ReasonNoRemote}
+
+// Reason is one condition that made a repository appear in the dirty list.
+type Reason struct {
+	// Kind is one of the Reason* constants.
+	Kind string
+	// Detail is a short human-readable description (e.g. "feature: 2 unpushed commits").
+	Detail string
+	// Unix is when the condition started (e.g. oldest unpushed commit), or 0 when unknown.
+	Unix int64
+	// Rule is the name of the rule that included it; empty for the default policy.
+	Rule string
+}
+
+// PolicyRule decides whether reasons of one kind count towards dirtiness.
+// Rules are checked in order and the first whose conditions all match a reason
+// decides it; reasons no rule matches fall back to the default policy.
+type PolicyRule struct {
+	// Name labels the rule in reasons; defaults to "rules[<index>]".
+	Name string `yaml:"name"`
+	// When is the reason kind the rule applies to (e.g. "unpushed").
+	When string `yaml:"when"`
+	// UntrackedOnly matches "changes" only when every change is an untracked file.
+	UntrackedOnly bool `yaml:"untrackedonly"`
+	// LocalOnly matches "unpushed" only for branches with no same-named remote ref.
+	LocalOnly bool `yaml:"localonly"`
+	// OlderThan and YoungerThan compare against the reason's start time;
+	// reasons with no known time never match an age condition.
+	OlderThan   time.Duration `yaml:"olderthan"`
+	YoungerThan time.Duration `yaml:"youngerthan"`
+	// Action is "include" (the reason makes the repo dirty) or "ignore".
+	Action string `yaml:"action"`
+}
+
+// validatePolicyRule checks one rule's kind, action, and ages.
+func validatePolicyRule(r PolicyRule) error {
+	if !slices.Contains(reasonKinds, r.When) {
+		return fmt.Errorf("when %q: want one of %s", r.When, strings.Join(reasonKinds, ", "))
+	}
+	if r.Action != "include" && r.Action != "ignore" {
+		return fmt.Errorf("action %q: want include or ignore", r.Action)
+	}
+	if r.OlderThan < 0 || r.YoungerThan < 0 {
+		return fmt.Errorf("olderthan/youngerthan must not be negative")
+	}
+	return nil
+}
+
+// candidateReason is a reason before the policy decides on it.
+type candidateReason struct {
+	Reason
+	untrackedOnly bool
+	localOnly     bool
+}
+
+// matches reports whether rule applies to c at now.
+func (r PolicyRule) matches(c candidateReason, now time.Time) bool {
+	if r.When != c.Kind {
+		return false
+	}
+	if r.UntrackedOnly && !c.untrackedOnly {
+		return false
+	}
+	if r.LocalOnly && !c.localOnly {
+		return false
+	}
+	if r.OlderThan > 0 || r.YoungerThan > 0 {
+		if c.Unix == 0 {
+			return false
+		}
+		age := now.Sub(time.Unix(c.Unix, 0))
+		if r.OlderThan > 0 && age < r.OlderThan {
+			return false
+		}
+		if r.YoungerThan > 0 && age >= r.YoungerThan {
+			return false
+		}
+	}
+	return true
+}
+
+// candidateReasons lists every condition found in rs, before rules apply.
+func candidateReasons(c *Config, rs *RepoStatus) []candidateReason {
+	var out []candidateReason
+	if n := len(rs.Porcelain.Entries); n > 0 {
+		untracked := true
+		for _, e := range rs.Porcelain.Entries {
+			if e.Staging != '?' || e.Worktree != '?' {
+				untracked = false
+				break
+			}
+		}
+		out = append(out, candidateReason{
+			Reason:        Reason{Kind: ReasonChanges, Detail: plural(n, "uncommitted change"), Unix: rs.DirtySinceUnix},
+			untrackedOnly: untracked,
+		})
+	}
+	for _, lb := range rs.Branches {
+		if c.ShouldHideLocalOnlyBranch(lb) || !lb.HasUnpushedChanges() {
+			continue
+		}
+		detail := lb.Name + ": differs from remote"
+		if lb.UnpushedCount > 0 {
+			detail = lb.Name + ": " + plural(lb.UnpushedCount, "unpushed commit")
+		}
+		out = append(out, candidateReason{
+			Reason:    Reason{Kind: ReasonUnpushed, Detail: detail, Unix: lb.OldestUnpushedUnix},
+			localOnly: lb.IsLocalOnly(),
+		})
+	}
+	if rs.StashCount > 0 {
+		out = append(out, candidateReason{Reason: Reason{
+			Kind: ReasonStash, Detail: plural(rs.StashCount, "stash entry"), Unix: rs.NewestStashUnix,
+		}})
+	}
+	if n := len(rs.LostCommits); n > 0 {
+		out = append(out, candidateReason{Reason: Reason{
+			Kind: ReasonLostCommits, Detail: plural(n, "lost commit"), Unix: rs.LostCommits[0].ReflogUnix,
+		}})
+	}
+	if rs.NoRemote {
+		out = append(out, candidateReason{Reason: Reason{
+			Kind: ReasonNoRemote, Detail: "no remote, " + plural(rs.CommitCount, "commit"), Unix: rs.LastCommitUnix,
+		}})
+	}
+	return out
+}
+
+// defaultIncludes is the built-in policy for reasons no rule matched.
+func defaultIncludes(c *Config, dir string, r candidateReason) bool {
+	switch r.Kind {
+	case ReasonStash:
+		return false
+	case ReasonNoRemote:
+		return c.NoRemote.Include && !c.IsNoRemoteAllowed(dir)
+	}
+	return true
+}
+
+// EvaluatePolicy applies the config's rules to rs, records the reasons that
+// count in rs.Reasons, and reports whether the repo is dirty.
+func EvaluatePolicy(c *Config, dir string, rs *RepoStatus, now time.Time) bool {
+	rs.Reasons = nil
+	for _, cand := range candidateReasons(c, rs) {
+		include := defaultIncludes(c, dir, cand)
+		for i, rule := range c.Rules {
+			if !rule.matches(cand, now) {
+				continue
+			}
+			include = rule.Action == "include"
+			cand.Rule = rule.Name
+			if cand.Rule == "" {
+				cand.Rule = "rules[" + strconv.Itoa(i) + "]"
+			}
+			break
+		}
+		if include {
+			rs.Reasons = append(rs.Reasons, cand.Reason)
+		}
+	}
+	return len(rs.Reasons) > 0
+}
+
+// plural formats n with noun, adding "s" (or "ies" for "entry") when n != 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "entry") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
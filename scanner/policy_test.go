@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func TestEvaluatePolicyRules(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	untracked := PorcelainStatus{Entries: []PorcelainEntry{{Staging: git.Untracked, Worktree: git.Untracked, Path: "scratch.txt"}}}
+	unpushed := func(name string, local bool, age time.Duration) LocalBranchRef {
+		lb := LocalBranchRef{
+			Name:               name,
+			UnpushedCount:      2,
+			OldestUnpushedUnix: now.Add(-age).Unix(),
+			Locations:          []BranchLocation{{Name: "local", Exists: true, TipHash: "aaa"}},
+		}
+		if !local {
+			lb.Locations = append(lb.Locations, BranchLocation{Name: "origin", Exists: true, TipHash: "bbb"})
+		}
+		return lb
+	}
+
+	tests := []struct {
+		name  string
+		rules []PolicyRule
+		rs    RepoStatus
+		want  []string
+	}{
+		{
+			name: "default counts changes",
+			rs:   RepoStatus{Porcelain: untracked},
+			want: []string{"changes: 1 uncommitted change"},
+		},
+		{
+			name:  "untrackedonly ignore",
+			rules: []PolicyRule{{When: ReasonChanges, UntrackedOnly: true, Action: "ignore"}},
+			rs:    RepoStatus{Porcelain: untracked},
+		},
+		{
+			name:  "young unpushed ignored, old one kept",
+			rules: []PolicyRule{{Name: "recent", When: ReasonUnpushed, YoungerThan: 48 * time.Hour, Action: "ignore"}},
+			rs: RepoStatus{Branches: []LocalBranchRef{
+				unpushed("new", false, time.Hour),
+				unpushed("old", false, 72*time.Hour),
+			}},
+			want: []string{"unpushed: old: 2 unpushed commits"},
+		},
+		{
+			name:  "localonly youngerthan",
+			rules: []PolicyRule{{When: ReasonUnpushed, LocalOnly: true, YoungerThan: time.Hour, Action: "ignore"}},
+			rs: RepoStatus{Branches: []LocalBranchRef{
+				unpushed("spike", true, time.Minute),
+				unpushed("shared", false, time.Minute),
+			}},
+			want: []string{"unpushed: shared: 2 unpushed commits"},
+		},
+		{
+			name: "stash ignored by default",
+			rs:   RepoStatus{StashCount: 2, NewestStashUnix: now.Unix()},
+		},
+		{
+			name:  "stash included by rule",
+			rules: []PolicyRule{{When: ReasonStash, Action: "include"}},
+			rs:    RepoStatus{StashCount: 2, NewestStashUnix: now.Unix()},
+			want:  []string{"stash: 2 stash entries [rules[0]]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Rules: tt.rules}
+			rs := tt.rs
+			include := EvaluatePolicy(cfg, "/repo", &rs, now)
+			var got []string
+			for _, r := range rs.Reasons {
+				s := r.Kind + ": " + r.Detail
+				if r.Rule != "" {
+					s += " [" + r.Rule + "]"
+				}
+				got = append(got, s)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("reasons = %q, want %q", got, tt.want)
+			}
+			if include != (len(tt.want) > 0) {
+				t.Fatalf("include = %v with reasons %q", include, got)
+			}
+		})
+	}
+}
+
+func TestStatusForRepoStashReason(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "r")
+	gitMinimalInit(t, repo)
+	gitCommitFile(t, repo, "a.txt", "a\n", "one")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	execGit(t, repo, "stash")
+
+	cfg := &Config{}
+	rs, include, err := StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	if include || rs.StashCount != 1 || rs.NewestStashUnix == 0 {
+		t.Fatalf("include=%v StashCount=%d NewestStashUnix=%d", include, rs.StashCount, rs.NewestStashUnix)
+	}
+
+	cfg.Rules = []PolicyRule{{Name: "stashes", When: ReasonStash, Action: "include"}}
+	rs, include, err = StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	if !include || len(rs.Reasons) != 1 || rs.Reasons[0].Rule != "stashes" {
+		t.Fatalf("include=%v reasons=%+v", include, rs.Reasons)
+	}
+}
+
+func TestParseConfigFileInvalidPolicyRule(t *testing.T) {
+	for _, content := range []string{
+		"rules:\n  - when: dirty\n    action: include\n",
+		"rules:\n  - when: stash\n    action: maybe\n",
+		"rules:\n  - when: unpushed\n    olderthan: -1h\n    action: ignore\n",
+	} {
+		cfgPath := filepath.Join(t.TempDir(), "bad-rule.yml")
+		if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := ParseConfigFile(cfgPath, ""); err == nil || !strings.Contains(err.Error(), "rules[0]") {
+			t.Fatalf("config %q: err = %v", content, err)
+		}
+	}
+}
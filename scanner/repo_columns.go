@@ -0,0 +1,36 @@
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Repository pane summary columns (repolist.columns), in default order.
+const (
+	RepoColumnStatus   = "status"
+	RepoColumnBranch   = "branch"
+	RepoColumnSync     = "sync"
+	RepoColumnStash    = "stash"
+	RepoColumnDirty    = "dirty"
+	RepoColumnUnpushed = "unpushed"
+)
+
+// RepoColumns lists every summary column; it is the default when
+// repolist.columns is empty.
+var RepoColumns = []string{
+	RepoColumnStatus, RepoColumnBranch, RepoColumnSync, RepoColumnStash, RepoColumnDirty, RepoColumnUnpushed,
+}
+
+// validateRepoListColumns rejects unknown or repeated column names.
+func validateRepoListColumns(c *Config) error {
+	for i, name := range c.RepoList.Columns {
+		if !slices.Contains(RepoColumns, name) {
+			return fmt.Errorf("repolist.columns[%d]: %q: want one of %s", i, name, strings.Join(RepoColumns, ", "))
+		}
+		if slices.Index(c.RepoList.Columns, name) != i {
+			return fmt.Errorf("repolist.columns[%d]: %q listed twice", i, name)
+		}
+	}
+	return nil
+}
@@ -15,7 +15,8 @@ func reportProgress(onProgress func(ScanProgress), p ScanProgress) {
 	}
 }
 
-// Scan finds all "dirty" git repositories specified by config.
+// Scan finds all "dirty" git repositories specified by config, plus those
+// that are only behind a remote (see [RepoStatus.BehindOnly]).
 func Scan(ctx context.Context, config *Config) (*MultiGitStatus, error) {
 	return ScanWithProgress(ctx, config, nil)
 }
@@ -51,7 +52,7 @@ func ScanWithProgress(ctx context.Context, config *Config, onProgress func(ScanP
 
 	results := NewMultiGitStatus()
 	var eg errgroup.Group
-	ex := NewExcluder(config.GitIgnore.FileGlob, config.GitIgnore.DirGlob)
+	ex := configExcluder(config)
 
 	for d := range repositories {
 		eg.Go(func() error {
@@ -76,7 +77,7 @@ func ScanWithProgress(ctx context.Context, config *Config, onProgress func(ScanP
 				CurrentPath:  d,
 			})
 
-			if include {
+			if include || rs.BehindOnly {
 				results.AddResult(d, rs)
 			}
 			return nil
@@ -91,11 +92,19 @@ func ScanWithProgress(ctx context.Context, config *Config, onProgress func(ScanP
 	return results, w.err
 }
 
+// configExcluder builds the global [Excluder] from the config's gitignore section.
+func configExcluder(config *Config) Excluder {
+	return NewExcluder(config.GitIgnore.FileGlob, config.GitIgnore.DirGlob, config.GitIgnore.Patterns).
+		WithRules(config.GitIgnore.Rules)
+}
+
 // StatusForRepo returns fresh status for a single repository directory using the
 // same porcelain filtering and branch metadata as [ScanWithProgress]. The bool
-// is whether this repo should appear in the dirty list (!clean or remote mismatch).
+// is whether this repo should appear in the dirty list, as decided by
+// [EvaluatePolicy]; repos that are only behind a remote report false with
+// [RepoStatus.BehindOnly] set.
 func StatusForRepo(config *Config, dir string) (RepoStatus, bool, error) {
-	ex := NewExcluder(config.GitIgnore.FileGlob, config.GitIgnore.DirGlob)
+	ex := configExcluder(config)
 	return statusForRepoWithExcluder(config, ex, dir)
 }
 
@@ -106,22 +115,50 @@ func statusForRepoWithExcluder(config *Config, ex Excluder, dir string) (RepoSta
 	if err != nil {
 		return RepoStatus{}, false, err
 	}
-	porcelain = ex.FilterPorcelainStatus(porcelain)
+	repoEx, err := ex.ForRepo(dir)
+	if err != nil {
+		// Best-effort: fall back to the global patterns alone.
+		slog.Warn("repo ignore patterns failed", "dir", dir, "err", err)
+	}
+	porcelain, hidden := repoEx.FilterPorcelainStatusCounted(porcelain)
 	branch, detached, branches, err := GitBranchStatus(dir)
 	if err != nil {
-		// Best-effort: a single repo's branch metadata failure should not abort the
-		// whole scan. The repo will still appear if it has uncommitted working-tree
-		// changes; it will just show no branch divergence information.
+		// Best-effort, like the lost commit, change time, remote and stash checks
+		// below: a single repo's metadata failure should not abort the whole scan.
+		// The repo will still appear if it has uncommitted working-tree changes;
+		// it will just show no branch divergence information.
 		slog.Warn("branch status scan failed", "dir", dir, "err", err)
 	}
 
+	lost, err := FindLostCommits(dir, config.LostCommits.MaxAge, time.Now())
+	if err != nil {
+		slog.Warn("lost commit scan failed", "dir", dir, "err", err)
+	}
+
 	rs := RepoStatus{
-		Branch:    branch,
-		Detached:  detached,
-		Porcelain: porcelain,
-		Branches:  branches,
+		Branch:      branch,
+		Detached:    detached,
+		Porcelain:   porcelain,
+		Hidden:      hidden,
+		Branches:    branches,
+		LostCommits: lost,
+	}
+	if rs.DirtySinceUnix, rs.LastModifiedUnix, err = changeTimes(dir, porcelain.Entries); err != nil {
+		slog.Warn("dirty-since check failed", "dir", dir, "err", err)
+	}
+	if err := fillNoRemote(dir, &rs); err != nil {
+		slog.Warn("remote check failed", "dir", dir, "err", err)
+	}
+	if err := fillStashes(dir, &rs); err != nil {
+		slog.Warn("stash list failed", "dir", dir, "err", err)
 	}
 	rs.FilteredBranches = rs.Filter(config)
-	include := !porcelain.ToGitStatus().IsClean() || rs.HasUnpushedChanges(config)
+	now := time.Now()
+	include := EvaluatePolicy(config, dir, &rs, now)
+	rs.Score = ComputeRiskScore(config, dir, &rs, now)
+	if !include {
+		n, _ := rs.Behind()
+		rs.BehindOnly = n > 0
+	}
 	return rs, include, nil
 }
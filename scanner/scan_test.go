@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParsePorcelainStatus(t *testing.T) {
@@ -77,6 +78,8 @@ gitignore:
   dirglob:
     - node_modules
 followsymlinks: true
+lostcommits:
+  maxage: 48h
 `
 
 	cfg, err := ParseConfigFile(cfgPath, defaultCfg)
@@ -89,6 +92,9 @@ followsymlinks: true
 	if !cfg.FollowSymlinks {
 		t.Fatal("expected followsymlinks=true from default config")
 	}
+	if cfg.LostCommits.MaxAge != 48*time.Hour {
+		t.Fatalf("lostcommits.maxage = %v, want 48h", cfg.LostCommits.MaxAge)
+	}
 }
 
 func TestParseConfigFileReadsFile(t *testing.T) {
@@ -216,3 +222,81 @@ func TestStatusForRepo(t *testing.T) {
 		t.Fatalf("want staged added, got %+v", rs2.Porcelain.Entries[0])
 	}
 }
+
+func TestStatusForRepoNoRemote(t *testing.T) {
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "scratch")
+	gitMinimalInit(t, repo)
+	gitCommitFile(t, repo, "a.txt", "a\n", "one")
+	gitCommitFile(t, repo, "a.txt", "b\n", "two")
+
+	cfg := &Config{}
+	rs, include, err := StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	if include {
+		t.Fatal("clean no-remote repo should not be listed unless noremote.include is set")
+	}
+	if !rs.NoRemote || rs.CommitCount != 2 || rs.LastCommitUnix == 0 {
+		t.Fatalf("NoRemote=%v CommitCount=%d LastCommitUnix=%d", rs.NoRemote, rs.CommitCount, rs.LastCommitUnix)
+	}
+
+	cfg.NoRemote.Include = true
+	if _, include, _ = StatusForRepo(cfg, repo); !include {
+		t.Fatal("noremote.include should list the repo")
+	}
+	cfg.NoRemote.Allow = []string{filepath.Join(tmp, "scr*")}
+	if _, include, _ = StatusForRepo(cfg, repo); include {
+		t.Fatal("noremote.allow glob should leave the repo out")
+	}
+
+	empty := filepath.Join(tmp, "empty")
+	gitMinimalInit(t, empty)
+	if rs, _, _ := StatusForRepo(cfg, empty); rs.NoRemote {
+		t.Fatal("repository without commits should not be flagged")
+	}
+}
+
+func TestParseConfigFileInvalidNoRemoteGlob(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "bad-glob.yml")
+	content := `
+noremote:
+  include: true
+  allow:
+    - "/src/[a-"
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := ParseConfigFile(cfgPath, ""); err == nil {
+		t.Fatal("ParseConfigFile() expected error for invalid glob")
+	}
+}
+
+func TestParseConfigFileInvalidGitignorePattern(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "bad-pattern.yml")
+	content := `
+gitignore:
+  patterns:
+    - "docs/[a-/x"
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := ParseConfigFile(cfgPath, ""); err == nil {
+		t.Fatal("ParseConfigFile() expected error for invalid pattern")
+	}
+}
+
+func TestParseConfigFileInvalidRepoListColumn(t *testing.T) {
+	for _, cols := range []string{"[status, bogus]", "[stash, stash]"} {
+		cfgPath := filepath.Join(t.TempDir(), "bad-columns.yml")
+		if err := os.WriteFile(cfgPath, []byte("repolist:\n  columns: "+cols+"\n"), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := ParseConfigFile(cfgPath, ""); err == nil {
+			t.Fatalf("columns %s: expected error", cols)
+		}
+	}
+}
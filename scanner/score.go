@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ScoreWeights are the points each unit of risk adds to a repository's score.
+type ScoreWeights struct {
+	// UnpushedCommit is per commit no remote has, summed over shown branches.
+	UnpushedCommit float64 `yaml:"unpushedcommit"`
+	// ChangedFile is per uncommitted path (after ignores).
+	ChangedFile float64 `yaml:"changedfile"`
+	// AgeDay is per whole day since the oldest uncommitted change or unpushed commit.
+	AgeDay float64 `yaml:"ageday"`
+	// Stash is per stash entry.
+	Stash float64 `yaml:"stash"`
+	// NoRemote is added once when the repository has commits but no remote.
+	NoRemote float64 `yaml:"noremote"`
+}
+
+// DefaultScoreWeights apply to the weights the config leaves out.
+var DefaultScoreWeights = ScoreWeights{
+	UnpushedCommit: 3,
+	ChangedFile:    1,
+	AgeDay:         0.5,
+	Stash:          2,
+	NoRemote:       10,
+}
+
+// ScoreConfig is the score section of the config, with the fields of
+// [ScoreWeights]. A weight left out keeps its default, so an explicit 0 turns
+// that component off.
+type ScoreConfig struct {
+	UnpushedCommit *float64 `yaml:"unpushedcommit"`
+	ChangedFile    *float64 `yaml:"changedfile"`
+	AgeDay         *float64 `yaml:"ageday"`
+	Stash          *float64 `yaml:"stash"`
+	NoRemote       *float64 `yaml:"noremote"`
+}
+
+// ScoreWeights returns the configured weights, taking each one the config
+// leaves out from [DefaultScoreWeights].
+func (c *Config) ScoreWeights() ScoreWeights {
+	w := DefaultScoreWeights
+	if c == nil {
+		return w
+	}
+	for _, f := range []struct {
+		set *float64
+		to  *float64
+	}{
+		{c.Score.UnpushedCommit, &w.UnpushedCommit},
+		{c.Score.ChangedFile, &w.ChangedFile},
+		{c.Score.AgeDay, &w.AgeDay},
+		{c.Score.Stash, &w.Stash},
+		{c.Score.NoRemote, &w.NoRemote},
+	} {
+		if f.set != nil {
+			*f.to = *f.set
+		}
+	}
+	return w
+}
+
+// validateScoreWeights rejects negative weights.
+func validateScoreWeights(w ScoreWeights) error {
+	if w.UnpushedCommit < 0 || w.ChangedFile < 0 || w.AgeDay < 0 || w.Stash < 0 || w.NoRemote < 0 {
+		return fmt.Errorf("score: weights must not be negative")
+	}
+	return nil
+}
+
+// Score component names, in report order.
+const (
+	ScoreUnpushedCommits = "unpushed_commits"
+	ScoreChangedFiles    = "changed_files"
+	ScoreAgeDays         = "age_days"
+	ScoreStashes         = "stashes"
+	ScoreNoRemote        = "no_remote"
+)
+
+// ScoreComponent is one input to a [RiskScore]: Count units at Weight points each.
+type ScoreComponent struct {
+	Name   string
+	Count  int
+	Weight float64
+}
+
+// Points is Count times Weight.
+func (sc ScoreComponent) Points() float64 {
+	return float64(sc.Count) * sc.Weight
+}
+
+// RiskScore ranks how much unsaved work a repository holds; higher is riskier.
+type RiskScore struct {
+	Total      float64
+	Components []ScoreComponent
+}
+
+// ComputeRiskScore scores rs, the status of the repository at dir, with the
+// config's weights. Components with a zero count are omitted, and a missing
+// remote does not count for repositories matching noremote.allow.
+func ComputeRiskScore(c *Config, dir string, rs *RepoStatus, now time.Time) RiskScore {
+	w := c.ScoreWeights()
+	oldest := rs.OldestUnpushedUnix()
+	if len(rs.Porcelain.Entries) > 0 && rs.DirtySinceUnix > 0 && (oldest == 0 || rs.DirtySinceUnix < oldest) {
+		oldest = rs.DirtySinceUnix
+	}
+	ageDays := 0
+	if oldest > 0 {
+		ageDays = int(math.Max(0, now.Sub(time.Unix(oldest, 0)).Hours()/24))
+	}
+	noRemote := 0
+	if rs.NoRemote && !c.IsNoRemoteAllowed(dir) {
+		noRemote = 1
+	}
+	var s RiskScore
+	for _, sc := range []ScoreComponent{
+		{Name: ScoreUnpushedCommits, Count: rs.UnpushedCommitCount(), Weight: w.UnpushedCommit},
+		{Name: ScoreChangedFiles, Count: len(rs.Porcelain.Entries), Weight: w.ChangedFile},
+		{Name: ScoreAgeDays, Count: ageDays, Weight: w.AgeDay},
+		{Name: ScoreStashes, Count: rs.StashCount, Weight: w.Stash},
+		{Name: ScoreNoRemote, Count: noRemote, Weight: w.NoRemote},
+	} {
+		if sc.Count == 0 {
+			continue
+		}
+		s.Components = append(s.Components, sc)
+		s.Total += sc.Points()
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestComputeRiskScore(t *testing.T) {
+	now := time.Unix(100*24*3600, 0)
+	rs := RepoStatus{
+		Porcelain:        PorcelainStatus{Entries: []PorcelainEntry{{Path: "a"}, {Path: "b"}}},
+		DirtySinceUnix:   now.Add(-4 * 24 * time.Hour).Unix(),
+		FilteredBranches: []LocalBranchRef{{Name: "x", UnpushedCount: 3, OldestUnpushedUnix: now.Add(-time.Hour).Unix()}},
+		StashCount:       1,
+	}
+
+	s := ComputeRiskScore(&Config{}, "/work/r", &rs, now)
+	// defaults: 3 unpushed x3 + 2 files x1 + 4 days x0.5 + 1 stash x2
+	if s.Total != 15 || len(s.Components) != 4 {
+		t.Fatalf("default score = %+v", s)
+	}
+
+	// Weights left out keep their defaults.
+	five, zero := 5.0, 0.0
+	cfg := &Config{Score: ScoreConfig{NoRemote: &five, Stash: &zero}}
+	rs.NoRemote = true
+	s = ComputeRiskScore(cfg, "/work/r", &rs, now)
+	if s.Total != 18 {
+		t.Fatalf("configured score = %+v, want 18", s)
+	}
+	if s.Components[len(s.Components)-1].Name != ScoreNoRemote {
+		t.Fatalf("components out of order: %+v", s.Components)
+	}
+
+	cfg.NoRemote.Allow = []string{"/work/*"}
+	if s = ComputeRiskScore(cfg, "/work/r", &rs, now); s.Total != 13 {
+		t.Fatalf("score for an allowed local-only repo = %+v, want 13", s)
+	}
+}
+
+func TestParseConfigFilePartialScoreWeights(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "score.yml")
+	if err := os.WriteFile(cfgPath, []byte("score:\n  stash: 0\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := ParseConfigFile(cfgPath, "")
+	if err != nil {
+		t.Fatalf("ParseConfigFile: %v", err)
+	}
+	want := DefaultScoreWeights
+	want.Stash = 0
+	if got := cfg.ScoreWeights(); got != want {
+		t.Fatalf("ScoreWeights = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseConfigFileNegativeScoreWeight(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "bad-score.yml")
+	if err := os.WriteFile(cfgPath, []byte("score:\n  stash: -1\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := ParseConfigFile(cfgPath, ""); err == nil {
+		t.Fatal("expected error for negative weight")
+	}
+}
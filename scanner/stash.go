@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fillStashes sets StashCount and NewestStashUnix from git stash list.
+func fillStashes(dir string, rs *RepoStatus) error {
+	out, err := runGit(dir, "stash", "list", "--format=%ct")
+	if err != nil || out == "" {
+		return err
+	}
+	lines := strings.Split(out, "\n")
+	// The stash list is newest first.
+	newest, err := strconv.ParseInt(lines[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	rs.StashCount = len(lines)
+	rs.NewestStashUnix = newest
+	return nil
+}
+
+// StashEntry is one git stash list entry.
+type StashEntry struct {
+	// Ref names the entry for git stash apply, pop and drop (e.g. "stash@{0}").
+	Ref string
+	// Hash is the stash commit; unlike Ref it does not shift when entries are added or dropped.
+	Hash string
+	// Message is the entry's reflog subject (e.g. "On main: wip").
+	Message string
+	// Unix is when the entry was created (Unix seconds).
+	Unix int64
+}
+
+// Stashes lists the repository's stash entries, newest first.
+func Stashes(dir string) ([]StashEntry, error) {
+	out, err := runGit(dir, "stash", "list", "--format=%gd%x00%H%x00%ct%x00%gs")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	var entries []StashEntry
+	for line := range strings.SplitSeq(out, "\n") {
+		parts := strings.SplitN(line, "\x00", 4)
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("unexpected git stash list line: %q", line)
+		}
+		unix, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse stash date %q: %w", parts[2], err)
+		}
+		entries = append(entries, StashEntry{Ref: parts[0], Hash: parts[1], Message: parts[3], Unix: unix})
+	}
+	return entries, nil
+}
@@ -0,0 +1,36 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStashes(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "r")
+	gitMinimalInit(t, repo)
+	gitCommitFile(t, repo, "a.txt", "a\n", "one")
+
+	entries, err := Stashes(repo)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Stashes without a stash = %+v, %v", entries, err)
+	}
+
+	for _, msg := range []string{"first", "second"} {
+		if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte(msg+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		execGit(t, repo, "stash", "push", "-m", msg)
+	}
+	entries, err = Stashes(repo)
+	if err != nil {
+		t.Fatalf("Stashes: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Ref != "stash@{0}" || entries[0].Message != "On main: second" ||
+		entries[1].Ref != "stash@{1}" || entries[1].Message != "On main: first" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if entries[0].Hash == "" || entries[0].Unix == 0 {
+		t.Fatalf("newest entry = %+v, want a hash and a date", entries[0])
+	}
+}
@@ -3,9 +3,11 @@ package scanner
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 )
@@ -25,6 +27,16 @@ type RepoStatus struct {
 	// Porcelain is the parsed git status --porcelain output.
 	Porcelain PorcelainStatus
 
+	// DirtySinceUnix approximates when the working tree became dirty: the
+	// oldest mtime among the paths in Porcelain (0 when clean or unreadable).
+	// LastModifiedUnix is the newest of those mtimes.
+	DirtySinceUnix   int64
+	LastModifiedUnix int64
+
+	// Hidden counts porcelain entries left out of Porcelain, per ignore source
+	// (global config, .dirtygitignore, git config); sources that hid nothing are omitted.
+	Hidden []IgnoreSourceCount
+
 	// Full list of local branches with remote comparison data (Branches)
 	Branches []LocalBranchRef
 
@@ -34,6 +46,64 @@ type RepoStatus struct {
 	// iterates Branches directly with inline config filtering, so it does not
 	// use FilteredBranches. It is always a subset of Branches with the same order.
 	FilteredBranches []LocalBranchRef
+
+	// LostCommits are reflog commits that no branch, tag, or remote-tracking ref
+	// can reach (see [FindLostCommits]). Empty when lostcommits.maxage is unset.
+	LostCommits []LostCommit
+
+	// BehindOnly is true when the repo is otherwise clean but a remote has
+	// commits that a local branch has not pulled (see [RepoStatus.Behind]).
+	// Scans keep such repos so callers can offer them as an optional category.
+	BehindOnly bool
+
+	// NoRemote is true when the repository has commits but no configured
+	// remote, so nothing backs it up. CommitCount and LastCommitUnix describe
+	// its local branches and are only filled when NoRemote is set.
+	NoRemote       bool
+	CommitCount    int
+	LastCommitUnix int64
+
+	// StashCount is the number of stash entries; NewestStashUnix is the newest
+	// entry's commit date (0 when there are none).
+	StashCount      int
+	NewestStashUnix int64
+
+	// Reasons are why the repo counts as dirty, as decided by [EvaluatePolicy].
+	Reasons []Reason
+
+	// Score ranks how much unsaved work the repo holds (see [ComputeRiskScore]).
+	Score RiskScore
+}
+
+// UnpushedCommitCount sums UnpushedCount across FilteredBranches.
+func (rs *RepoStatus) UnpushedCommitCount() int {
+	n := 0
+	for _, lb := range rs.FilteredBranches {
+		n += lb.UnpushedCount
+	}
+	return n
+}
+
+// NewestCommitUnix returns the newest local branch tip's committer date, or 0
+// when there are no branches.
+func (rs *RepoStatus) NewestCommitUnix() int64 {
+	var newest int64
+	for _, lb := range rs.Branches {
+		newest = max(newest, lb.TipUnix)
+	}
+	return newest
+}
+
+// OldestUnpushedUnix returns the committer date of the oldest unpushed commit
+// across FilteredBranches, or 0 when no shown branch has unpushed commits.
+func (rs *RepoStatus) OldestUnpushedUnix() int64 {
+	var oldest int64
+	for _, lb := range rs.FilteredBranches {
+		if t := lb.OldestUnpushedUnix; t > 0 && (oldest == 0 || t < oldest) {
+			oldest = t
+		}
+	}
+	return oldest
 }
 
 // LocalBranchRef is one local branch tip (refs/heads/*).
@@ -48,6 +118,26 @@ type LocalBranchRef struct {
 	TipUnix int64
 	// Current is true when this row is the checked-out branch.
 	Current bool
+	// Upstream is the configured upstream ref (e.g. refs/remotes/origin/feature);
+	// empty when the branch does not track anything.
+	Upstream string
+	// UpstreamGone is true when Upstream is configured but the ref no longer
+	// exists, typically after git fetch --prune dropped a deleted remote branch.
+	UpstreamGone bool
+	// MergedInto is the remote default branch (e.g. "origin/main", from
+	// refs/remotes/<remote>/HEAD) that already contains every commit on this
+	// branch. Empty when unmerged or no default is known. GitBranchStatus sets
+	// it only for branches whose upstream is gone; see FillMergedInto.
+	MergedInto string
+	// UnmergedCount is set only when UpstreamGone and MergedInto is empty: commits
+	// on this branch not contained in any remote default branch. Zero when no
+	// remote default branch is known.
+	UnmergedCount int
+	// UnpushedCount is how many commits on this branch no remote-tracking ref
+	// contains, and OldestUnpushedUnix the oldest of their committer dates.
+	// Only filled for branches with [LocalBranchRef.HasUnpushedChanges].
+	UnpushedCount      int
+	OldestUnpushedUnix int64
 	// Locations compares this local branch to same-named refs on each configured
 	// remote; see [BranchLocation]. Empty when detached or before branch scan fills it.
 	Locations []BranchLocation
@@ -94,6 +184,43 @@ type BranchLocation struct {
 	HistoriesUnrelated bool
 }
 
+// IsGoneMerged reports whether the upstream was deleted and every commit is
+// already in a remote default branch, so the branch is safe to delete.
+func (lb LocalBranchRef) IsGoneMerged() bool {
+	return lb.UpstreamGone && lb.MergedInto != ""
+}
+
+// IsGoneUnmerged reports whether the upstream was deleted but the branch still
+// has commits that no remote default branch contains.
+func (lb LocalBranchRef) IsGoneUnmerged() bool {
+	return lb.UpstreamGone && lb.UnmergedCount > 0
+}
+
+// Behind returns the largest Incoming count across this branch's comparable
+// remotes and the committer date (Unix seconds) of the newest incoming remote
+// tip. Both are zero when no remote is ahead of the local branch.
+func (lb LocalBranchRef) Behind() (incoming int, newestUnix int64) {
+	for _, loc := range lb.Locations {
+		if loc.Name == "local" || !loc.Exists || loc.HistoriesUnrelated || loc.Incoming == 0 {
+			continue
+		}
+		incoming = max(incoming, loc.Incoming)
+		newestUnix = max(newestUnix, loc.TipUnix)
+	}
+	return incoming, newestUnix
+}
+
+// Behind sums [LocalBranchRef.Behind] over all local branches and returns the
+// newest incoming commit date across them.
+func (rs *RepoStatus) Behind() (incoming int, newestUnix int64) {
+	for _, lb := range rs.Branches {
+		n, unix := lb.Behind()
+		incoming += n
+		newestUnix = max(newestUnix, unix)
+	}
+	return incoming, newestUnix
+}
+
 // IsLocalOnly reports whether no configured remote has a same-named branch ref
 // (refs/remotes/<remote>/<name> missing for every remote). Repositories with no
 // remotes still populate only the local slot, which counts as local-only here.
@@ -311,6 +438,11 @@ type Config struct {
 	GitIgnore struct {
 		FileGlob []string `yaml:"fileglob"`
 		DirGlob  []string `yaml:"dirglob"`
+		// Patterns are .gitignore-style lines (anchoring, "**", trailing "/",
+		// and "!" negation), applied after FileGlob and DirGlob.
+		Patterns []string `yaml:"patterns"`
+		// Rules hide entries only when both path and status match.
+		Rules []FilterRule `yaml:"rules"`
 	} `yaml:"gitignore"`
 	FollowSymlinks bool `yaml:"followsymlinks"`
 	Branches       struct {
@@ -321,6 +453,30 @@ type Config struct {
 		// present as a local ref, even when tips match every remote.
 		Default []string `yaml:"default"`
 	} `yaml:"branches"`
+	// LostCommits configures the reflog scan for commits no ref can reach.
+	LostCommits struct {
+		// MaxAge limits which reflog entries are considered (e.g. "720h").
+		// Zero disables the analysis.
+		MaxAge time.Duration `yaml:"maxage"`
+	} `yaml:"lostcommits"`
+	// NoRemote configures repositories that have commits but no remote.
+	NoRemote struct {
+		// Include lists such repositories even when their working tree is clean.
+		Include bool `yaml:"include"`
+		// Allow holds path globs (filepath.Match syntax, matched against the
+		// repository path) of intentionally local-only repositories to leave out.
+		Allow []string `yaml:"allow"`
+	} `yaml:"noremote"`
+	// Rules adjust which reasons make a repository dirty; see [PolicyRule].
+	Rules []PolicyRule `yaml:"rules"`
+	// Score weights the risk score used to rank repositories; see [Config.ScoreWeights].
+	Score ScoreConfig `yaml:"score"`
+	// RepoList configures the TUI repository pane.
+	RepoList struct {
+		// Columns picks the summary columns shown after each path, in order
+		// (see [RepoColumns]); empty shows them all.
+		Columns []string `yaml:"columns"`
+	} `yaml:"repolist"`
 	// Edit holds argv for opening a repository from the UI (key "e").
 	Edit struct {
 		// Command is the program and arguments passed to exec (no shell).
@@ -329,9 +485,27 @@ type Config struct {
 		// is appended as the final argument. Empty means ["code", <repo>].
 		Command []string `yaml:"command"`
 	} `yaml:"edit"`
+	// Diff configures how the TUI Diff pane renders changes. Both options are on
+	// when omitted; see [Config.DiffHighlight] and [Config.DiffWordDiff].
+	Diff struct {
+		// Highlight colours diff bodies by the language of each file's extension.
+		Highlight *bool `yaml:"highlight"`
+		// WordDiff emphasises the changed words inside paired -/+ lines.
+		WordDiff *bool `yaml:"worddiff"`
+	} `yaml:"diff"`
 	localOnlyHideCompiled []*regexp.Regexp
 }
 
+// DiffHighlight reports whether the Diff pane colours diff bodies by language.
+func (c *Config) DiffHighlight() bool {
+	return c == nil || c.Diff.Highlight == nil || *c.Diff.Highlight
+}
+
+// DiffWordDiff reports whether the Diff pane emphasises changed words.
+func (c *Config) DiffWordDiff() bool {
+	return c == nil || c.Diff.WordDiff == nil || *c.Diff.WordDiff
+}
+
 // ShouldHideLocalOnlyBranch returns true when lb is local-only (see
 // LocalBranchRef.IsLocalOnly) and its short branch name matches any pattern in
 // branches.hidelocalonly.regex.
@@ -381,3 +555,16 @@ func (c *Config) EditArgv(absRepo string) ([]string, error) {
 	}
 	return out, nil
 }
+
+// IsNoRemoteAllowed reports whether dir matches any noremote.allow glob.
+func (c *Config) IsNoRemoteAllowed(dir string) bool {
+	if c == nil {
+		return false
+	}
+	for _, p := range c.NoRemote.Allow {
+		if ok, _ := filepath.Match(p, dir); ok {
+			return true
+		}
+	}
+	return false
+}
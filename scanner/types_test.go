@@ -3,6 +3,8 @@ package scanner
 import (
 	"reflect"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestConfigEditArgv(t *testing.T) {
@@ -60,6 +62,25 @@ func TestConfigEditArgv(t *testing.T) {
 	})
 }
 
+func TestConfigDiffOptionsDefaultOn(t *testing.T) {
+	t.Parallel()
+
+	var nilConfig *Config
+	if !nilConfig.DiffHighlight() || !nilConfig.DiffWordDiff() {
+		t.Fatal("nil config should enable highlight and word diff")
+	}
+	var c Config
+	if err := yaml.Unmarshal([]byte("diff:\n  worddiff: false\n"), &c); err != nil {
+		t.Fatal(err)
+	}
+	if !c.DiffHighlight() {
+		t.Fatal("omitted diff.highlight should default to true")
+	}
+	if c.DiffWordDiff() {
+		t.Fatal("diff.worddiff: false should turn word diff off")
+	}
+}
+
 func TestLocalBranchRefIsLocalOnly(t *testing.T) {
 	t.Parallel()
 
@@ -42,6 +42,7 @@ func Run(config *scanner.Config) error {
 	}
 	m.statusTable = newStatusTable()
 	m.branchTable = newBranchTable()
+	m.stashTable = newStashTable()
 	log.SetOutput(m.logBuf)
 
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
@@ -63,8 +64,19 @@ func (m *model) beginScan() tea.Cmd {
 	m.deleteRepoConfirmOpen = false
 	m.deleteStatusFileConfirmOpen = false
 	m.deleteStatusFilePendingRel = ""
+	m.deleteStatusFilePendingPaths = nil
 	m.checkoutStatusFileConfirmOpen = false
 	m.checkoutStatusFilePendingRel = ""
+	m.checkoutStatusFilePendingPaths = nil
+	m.discardHunkConfirmOpen = false
+	m.lostCommitsOpen = false
+	m.whyOpen = false
+	m.mergedBranchesOpen = false
+	m.commitOpen = false
+	m.pushOpen = false
+	m.branchMenuOpen = false
+	m.stashCreateOpen = false
+	m.stashDropConfirmOpen = false
 	m.scanning = true
 	m.scanProgress = scanner.ScanProgress{}
 	m.scanProgressCh = make(chan scanner.ScanProgress, 256)
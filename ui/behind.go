@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"log"
+	"slices"
+)
+
+// visibleRepoPaths lists the repository pane rows: scanned repositories in
+// display order (see [model.sortRepoPaths]), with group headers when grouping
+// is on (see [model.groupRepoPaths]). Repos that are only behind their remote
+// are hidden unless the behind category is shown (key b), and the / filter
+// dims the repositories it does not match (see [model.markRepoFilterHits]).
+func (m *model) visibleRepoPaths() []string {
+	if m.repositories == nil {
+		return nil
+	}
+	paths := m.repositories.SortedRepoPaths()
+	if !m.showBehind {
+		paths = slices.DeleteFunc(paths, func(p string) bool {
+			st, ok := m.repositories.Get(p)
+			return ok && st.BehindOnly
+		})
+	}
+	m.markRepoFilterHits(paths)
+	m.sortRepoPaths(paths)
+	return m.groupRepoPaths(paths)
+}
+
+// toggleShowBehind shows or hides behind-only repositories, keeping the
+// selected repository selected when it is still listed.
+func (m *model) toggleShowBehind() {
+	m.showBehind = !m.showBehind
+	m.rebuildRepoList()
+	if m.showBehind {
+		log.Printf("showing repositories that are behind their remote")
+	} else {
+		log.Printf("hiding repositories that are only behind their remote")
+	}
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+}
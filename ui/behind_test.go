@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestToggleShowBehindKeepsSelection(t *testing.T) {
+	m := newTestModel()
+	m.repositories.AddResult("/a", scanner.RepoStatus{Branch: "main"})
+	m.repositories.AddResult("/b", scanner.RepoStatus{
+		Branch:     "main",
+		BehindOnly: true,
+		Branches: []scanner.LocalBranchRef{{
+			Name: "main",
+			Locations: []scanner.BranchLocation{
+				{Name: "local", Exists: true, TipHash: "aaa"},
+				{Name: "origin", Exists: true, TipHash: "bbb", Incoming: 4},
+			},
+		}},
+	})
+	m.repositories.AddResult("/c", scanner.RepoStatus{Branch: "main"})
+	m.repoList = m.visibleRepoPaths()
+	if strings.Join(m.repoList, ",") != "/a,/c" {
+		t.Fatalf("behind-only repo should be hidden by default, got %v", m.repoList)
+	}
+	m.cursor = 1
+
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}}); !handled {
+		t.Fatal("b should be handled")
+	}
+	if strings.Join(m.repoList, ",") != "/a,/b,/c" || m.currentRepo() != "/c" {
+		t.Fatalf("after toggle: list=%v selected=%q", m.repoList, m.currentRepo())
+	}
+	if got := m.repoListSuffix("/b"); !strings.Contains(got, "↓4") {
+		t.Fatalf("suffix = %q, want incoming count", got)
+	}
+	if got := m.repoListSuffix("/a"); got != "" {
+		t.Fatalf("dirty repo should have no suffix, got %q", got)
+	}
+
+	m.toggleShowBehind()
+	if strings.Join(m.repoList, ",") != "/a,/c" || m.currentRepo() != "/c" {
+		t.Fatalf("after second toggle: list=%v selected=%q", m.repoList, m.currentRepo())
+	}
+}
+
+func TestRepoListSuffixNoRemote(t *testing.T) {
+	m := newTestModel()
+	m.repositories.AddResult("/solo", scanner.RepoStatus{Branch: "main", NoRemote: true, CommitCount: 42})
+	if got := m.repoListSuffix("/solo"); !strings.Contains(got, "no remote, 42 commits") {
+		t.Fatalf("suffix = %q", got)
+	}
+}
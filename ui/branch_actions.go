@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// branchAction is one row of the branch actions menu; key runs it directly.
+type branchAction struct {
+	key   string
+	label string
+}
+
+// branchActions are the branch menu rows, in display order.
+var branchActions = []branchAction{
+	{"c", "Check out"},
+	{"n", "New branch from HEAD"},
+	{"r", "Rename"},
+	{"d", "Delete (git branch -d, merged only)"},
+	{"D", "Force delete (git branch -D)"},
+	{"u", "Set upstream"},
+	{"U", "Unset upstream"},
+}
+
+// openBranchMenu shows the actions menu for the branch selected in the Branches pane.
+func (m *model) openBranchMenu() bool {
+	if m.focus != paneBranches || m.err != nil {
+		return false
+	}
+	lb, ok := m.selectedBranch()
+	if !ok {
+		return false
+	}
+	m.branchMenuOpen = true
+	m.branchMenuBranch = lb
+	m.branchMenuCursor = 0
+	m.branchForceDeleteConfirm = false
+	m.branchInput = textinput.New()
+	m.branchInput.CharLimit = 200
+	return true
+}
+
+// handleBranchMenuKey processes keys while the branch menu is open: the force
+// delete confirmation first, then the name input, then the menu itself.
+func (m *model) handleBranchMenuKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.branchForceDeleteConfirm {
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			m.branchForceDeleteConfirm = false
+		case "enter":
+			m.branchForceDeleteConfirm = false
+			if m.deleteConfirmYes {
+				m.runBranchGit("branch", "-D", m.branchMenuBranch.Name)
+			}
+		default:
+			m.handleConfirmNavKey(msg)
+		}
+		return m, nil
+	}
+	if m.branchInput.Focused() {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.branchInput.Blur()
+			return m, nil
+		case "enter":
+			m.submitBranchInput()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.branchInput, cmd = m.branchInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "m":
+		m.branchMenuOpen = false
+	case "up", "k":
+		m.branchMenuCursor = max(0, m.branchMenuCursor-1)
+	case "down", "j":
+		m.branchMenuCursor = min(len(branchActions)-1, m.branchMenuCursor+1)
+	case "enter":
+		return m, m.runBranchAction(branchActions[m.branchMenuCursor].key)
+	default:
+		for _, a := range branchActions {
+			if a.key == msg.String() {
+				return m, m.runBranchAction(a.key)
+			}
+		}
+	}
+	return m, nil
+}
+
+// runBranchAction starts the menu action with the given key. Actions that need a
+// name focus the input; force delete asks first; the rest run straight away.
+func (m *model) runBranchAction(key string) tea.Cmd {
+	lb := m.branchMenuBranch
+	prompt := func(p, value string) tea.Cmd {
+		m.branchInputAction = key
+		m.branchInput.Prompt = p
+		m.branchInput.SetValue(value)
+		m.branchInput.CursorEnd()
+		return m.branchInput.Focus()
+	}
+	switch key {
+	case "c":
+		m.runBranchGit("checkout", lb.Name)
+	case "n":
+		return prompt("New branch: ", "")
+	case "r":
+		return prompt("Rename to: ", lb.Name)
+	case "d":
+		m.runBranchGit("branch", "-d", lb.Name)
+	case "D":
+		m.branchForceDeleteConfirm = true
+		m.deleteConfirmYes = false
+		m.branchForceDeleteUnique = -1
+		// --exclude before --branches takes the name without refs/heads/.
+		out, err := runGitInRepo(m.currentRepo(), "rev-list", "--count", "refs/heads/"+lb.Name,
+			"--not", "--exclude="+lb.Name, "--branches", "--tags", "--remotes")
+		if err != nil {
+			log.Printf("git: %v", err)
+		} else if n, err := strconv.Atoi(out); err == nil {
+			m.branchForceDeleteUnique = n
+		}
+	case "u":
+		remote := upstreamRemote(lb.Upstream)
+		if remote == "" {
+			remote = "origin"
+		}
+		return prompt("Upstream: ", remote+"/"+lb.Name)
+	case "U":
+		m.runBranchGit("branch", "--unset-upstream", lb.Name)
+	}
+	return nil
+}
+
+// submitBranchInput runs the action waiting on the name input. The input stays
+// focused when git refuses, so the name can be corrected.
+func (m *model) submitBranchInput() {
+	value := strings.TrimSpace(m.branchInput.Value())
+	if value == "" {
+		return
+	}
+	var ok bool
+	switch m.branchInputAction {
+	case "n":
+		ok = m.runBranchGit("branch", "--", value)
+	case "r":
+		ok = m.runBranchGit("branch", "-m", m.branchMenuBranch.Name, value)
+	case "u":
+		ok = m.runBranchGit("branch", "--set-upstream-to="+value, m.branchMenuBranch.Name)
+	}
+	if ok {
+		m.branchInput.Blur()
+	}
+}
+
+// runBranchGit runs a branch command in the selected repository, logs it with its
+// outcome, closes the menu and refreshes that repository. On failure the menu
+// stays open so another action can be tried.
+func (m *model) runBranchGit(args ...string) bool {
+	repo := m.currentRepo()
+	log.Printf("git %s", strings.Join(args, " "))
+	out, err := runGitInRepo(repo, args...)
+	if err != nil {
+		log.Printf("git: %v", err)
+		return false
+	}
+	for line := range strings.SplitSeq(out, "\n") {
+		if line != "" {
+			log.Printf("git: %s", line)
+		}
+	}
+	m.branchMenuOpen = false
+	m.refreshRepoStatus(repo)
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+	return true
+}
+
+// renderBranchMenuOverlay lists the branch actions, the name input, or the force
+// delete confirmation with the commits that would be lost.
+func (m *model) renderBranchMenuOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+	lb := m.branchMenuBranch
+
+	parts := []string{
+		styleBold.Render(truncateASCII("Branch "+lb.Name, innerW)), "",
+		styleDim.Render(truncateASCII(m.currentRepo(), innerW)),
+	}
+	upstream := "Upstream: none"
+	if lb.Upstream != "" {
+		upstream = "Upstream: " + strings.TrimPrefix(lb.Upstream, "refs/remotes/")
+	}
+	parts = append(parts, styleDim.Render(truncateASCII(upstream, innerW)), "")
+
+	if m.branchForceDeleteConfirm {
+		msg := "git branch -D deletes the branch even if it is not merged. Commits on no " +
+			"other branch, tag or remote are lost with it."
+		unique := "Commits only on this branch: unknown"
+		if n := m.branchForceDeleteUnique; n >= 0 {
+			unique = fmt.Sprintf("%d commit(s) only on this branch", n)
+		}
+		parts = append(parts,
+			styleBold.Render("Force delete this branch?"), "",
+			truncateASCII(unique, innerW), "",
+			warnBlock(innerW).Render(msg), "",
+			deleteConfirmButtons(m.deleteConfirmYes), "",
+			deleteConfirmFooter())
+		return m.placeCenteredDimModal(roundedModal(boxW).Render(strings.Join(parts, "\n")))
+	}
+
+	for i, a := range branchActions {
+		line := truncateASCII(fmt.Sprintf("%-2s %s", a.key, a.label), innerW)
+		if i == m.branchMenuCursor {
+			line = styleSelRowFocused.Render(line)
+		}
+		parts = append(parts, line)
+	}
+	footer := "↑/↓ select · Enter or key run · Esc close"
+	if m.branchInput.Focused() {
+		m.branchInput.Width = max(1, innerW-len(m.branchInput.Prompt)-1)
+		parts = append(parts, "", m.branchInput.View())
+		footer = "Enter confirm · Esc back to the menu"
+	}
+	parts = append(parts, "", styleDim.Render(footer))
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(strings.Join(parts, "\n")))
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"log"
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestBranchMenuActions(t *testing.T) {
+	repo, _ := cloneWithBareOrigin(t)
+	runGitT(t, repo, "checkout", "-q", "-b", "topic")
+	runGitT(t, repo, "commit", "-q", "--allow-empty", "-m", "one")
+	runGitT(t, repo, "commit", "-q", "--allow-empty", "-m", "two")
+	runGitT(t, repo, "checkout", "-q", "main")
+
+	cfg := &scanner.Config{}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repoList = []string{repo}
+	m.focus = paneBranches
+	prevLog := log.Writer()
+	log.SetOutput(m.logBuf)
+	defer log.SetOutput(prevLog)
+
+	keys := func(ks ...string) {
+		t.Helper()
+		for _, k := range ks {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "ctrl+u":
+				msg = tea.KeyMsg{Type: tea.KeyCtrlU}
+			}
+			m.handleKey(msg)
+		}
+	}
+	// openMenu refreshes the repo and opens the menu on the named branch.
+	openMenu := func(name string) {
+		t.Helper()
+		m.refreshRepoStatus(repo)
+		m.syncViewports()
+		for i, lb := range m.branchRows {
+			if lb.Name == name {
+				m.branchTable.SetCursor(i)
+			}
+		}
+		if lb, ok := m.selectedBranch(); !ok || lb.Name != name {
+			t.Fatalf("branch %q not listed: %+v", name, m.branchRows)
+		}
+		keys("m")
+		if !m.branchMenuOpen {
+			t.Fatal("m should open the branch menu")
+		}
+	}
+
+	openMenu("topic")
+	keys("r", "ctrl+u", "feature", "enter")
+	if m.branchMenuOpen || runGitT(t, repo, "branch", "--list", "feature") == "" {
+		t.Fatal("rename should close the menu and rename the branch")
+	}
+
+	openMenu("feature")
+	// The default origin/feature does not exist yet, so git refuses.
+	keys("u", "enter")
+	if !m.branchMenuOpen || !strings.Contains(m.logBuf.String(), "git branch --set-upstream-to=origin/feature feature") {
+		t.Fatalf("a failed set-upstream should keep the menu open:\n%s", m.logBuf.String())
+	}
+	keys("ctrl+u", "origin/main", "enter")
+	if got := strings.TrimSpace(runGitT(t, repo, "rev-parse", "--abbrev-ref", "feature@{upstream}")); got != "origin/main" {
+		t.Fatalf("upstream = %q", got)
+	}
+
+	openMenu("feature")
+	keys("U")
+	if out, err := exec.Command("git", "-C", repo, "rev-parse", "--abbrev-ref", "feature@{upstream}").CombinedOutput(); err == nil {
+		t.Fatalf("upstream should be unset, got %s", out)
+	}
+
+	openMenu("feature")
+	keys("c")
+	if got := strings.TrimSpace(runGitT(t, repo, "branch", "--show-current")); got != "feature" {
+		t.Fatalf("current branch = %q", got)
+	}
+
+	openMenu("feature")
+	keys("n", "spike", "enter")
+	if runGitT(t, repo, "rev-parse", "spike") != runGitT(t, repo, "rev-parse", "HEAD") {
+		t.Fatal("new branch should point at HEAD")
+	}
+
+	// Safe delete refuses the unmerged branch; force delete confirms first.
+	runGitT(t, repo, "checkout", "-q", "main")
+	openMenu("feature")
+	keys("d")
+	if runGitT(t, repo, "branch", "--list", "feature") == "" || !m.branchMenuOpen {
+		t.Fatal("git branch -d should refuse an unmerged branch")
+	}
+	// spike holds the same commits, so deleting feature would orphan none.
+	keys("D")
+	if v := m.View(); !strings.Contains(v, "0 commit(s) only on this branch") {
+		t.Fatalf("force delete should count commits only this branch has:\n%s", v)
+	}
+	keys("esc")
+	runGitT(t, repo, "branch", "-D", "spike")
+	keys("D")
+	if v := m.View(); !strings.Contains(v, "2 commit(s) only on this branch") {
+		t.Fatalf("force delete should show what would be lost:\n%s", v)
+	}
+	keys("y", "enter")
+	if runGitT(t, repo, "branch", "--list", "feature") != "" {
+		t.Fatal("git branch -D should delete the branch")
+	}
+	if !strings.Contains(m.logBuf.String(), "git branch -D feature") {
+		t.Fatalf("log should name the command:\n%s", m.logBuf.String())
+	}
+}
@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/charmbracelet/bubbles/table"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// branchCommitRowsMax caps the unpushed commits listed under a branch.
+const branchCommitRowsMax = 50
+
+// branchRowDetail is what a row listed under an expanded branch stands for:
+// a remote copy of the branch, one of its unpushed commits, or the count of
+// unpushed commits past branchCommitRowsMax.
+type branchRowDetail struct {
+	remote scanner.BranchLocation
+	commit scanner.UnpushedCommit
+	more   int
+}
+
+// branchCommitKey identifies a repository's branch in branchExpanded.
+func branchCommitKey(repo, branch string) string {
+	return repo + "\x00" + branch
+}
+
+// unpushedCommits returns the branch's commits that no remote has. They are
+// loaded once per tip and unpushed count, so a new commit or a push reloads them.
+func (m *model) unpushedCommits(repo string, lb scanner.LocalBranchRef) []scanner.UnpushedCommit {
+	key := fmt.Sprintf("%s\x00%s\x00%d", branchCommitKey(repo, lb.Name), lb.TipHash, lb.UnpushedCount)
+	if commits, ok := m.branchCommitCache[key]; ok {
+		return commits
+	}
+	commits, err := scanner.UnpushedCommits(repo, lb.Name, branchCommitRowsMax)
+	if err != nil {
+		log.Printf("git: %v", err)
+	}
+	if m.branchCommitCache == nil {
+		m.branchCommitCache = make(map[string][]scanner.UnpushedCommit)
+	}
+	m.branchCommitCache[key] = commits
+	return commits
+}
+
+// branchExpandable reports whether Space has anything to list under the branch.
+func branchExpandable(lb scanner.LocalBranchRef) bool {
+	return lb.UnpushedCount > 0 || len(differingRemotes(lb)) > 0
+}
+
+// appendBranchDetailRows lists, under an expanded branch's row, each remote copy
+// that differs from it and then its unpushed commits (subject, short hash, age
+// and author).
+func (m *model) appendBranchDetailRows(rows []table.Row, repo string, lb scanner.LocalBranchRef) []table.Row {
+	if !branchExpandable(lb) || !m.branchExpanded[branchCommitKey(repo, lb.Name)] {
+		return rows
+	}
+	local, _ := localLocation(lb)
+	for _, loc := range differingRemotes(lb) {
+		m.branchRows = append(m.branchRows, lb)
+		m.branchRowDetails = append(m.branchRowDetails, branchRowDetail{remote: loc})
+		rows = append(rows, table.Row{
+			"  ↔ " + loc.Name + "/" + lb.Name,
+			shortHash(loc.TipHash),
+			relativeTime(loc.TipUnix),
+			branchRemoteSummaryFromLocations([]scanner.BranchLocation{local, loc}),
+		})
+	}
+	if lb.UnpushedCount == 0 {
+		return rows
+	}
+	commits := m.unpushedCommits(repo, lb)
+	for _, c := range commits {
+		m.branchRows = append(m.branchRows, lb)
+		m.branchRowDetails = append(m.branchRowDetails, branchRowDetail{commit: c})
+		rows = append(rows, table.Row{"  " + c.Subject, shortHash(c.Hash), relativeTime(c.Unix), c.Author})
+	}
+	if more := lb.UnpushedCount - len(commits); more > 0 && len(commits) == branchCommitRowsMax {
+		m.branchRows = append(m.branchRows, lb)
+		m.branchRowDetails = append(m.branchRowDetails, branchRowDetail{more: more})
+		rows = append(rows, table.Row{fmt.Sprintf("  … %d more", more), "", "", ""})
+	}
+	return rows
+}
+
+// selectedBranchDetail returns the remote or commit row under the Branches pane cursor.
+func (m *model) selectedBranchDetail() (branchRowDetail, bool) {
+	i := m.branchTable.Cursor()
+	if i < 0 || i >= len(m.branchRowDetails) || m.branchRowDetails[i] == (branchRowDetail{}) {
+		return branchRowDetail{}, false
+	}
+	return m.branchRowDetails[i], true
+}
+
+// toggleBranchDetails expands or collapses the rows under the selected branch.
+// On one of those rows it collapses the branch and selects the branch row.
+func (m *model) toggleBranchDetails() bool {
+	lb, ok := m.selectedBranch()
+	if !ok || !branchExpandable(lb) {
+		return false
+	}
+	if _, onDetail := m.selectedBranchDetail(); onDetail {
+		i := m.branchTable.Cursor()
+		for i > 0 && m.branchRowDetails[i] != (branchRowDetail{}) {
+			i--
+		}
+		m.branchTable.SetCursor(i)
+	}
+	if m.branchExpanded == nil {
+		m.branchExpanded = make(map[string]bool)
+	}
+	key := branchCommitKey(m.currentRepo(), lb.Name)
+	m.branchExpanded[key] = !m.branchExpanded[key]
+	m.syncViewports()
+	return true
+}
+
+// keepDiffDetail reports whether the Diff pane keeps showing a commit, remote
+// comparison or stash entry: while Diff itself or Log has focus, so a long
+// commit can be scrolled, and the repository it came from is still selected.
+func (m *model) keepDiffDetail() bool {
+	return (m.focus == paneDiff || m.focus == paneLog) && m.diffDetailRepo == m.currentRepo()
+}
+
+// syncBranchDiff points the Diff pane at the selected unpushed commit or remote
+// comparison while the Branches pane has focus. Once another pane that picks
+// what Diff shows has focus, Diff returns to the working tree.
+func (m *model) syncBranchDiff() {
+	if m.keepDiffDetail() {
+		return
+	}
+	var hash string
+	var cmp branchCompare
+	if d, ok := m.selectedBranchDetail(); ok && m.focus == paneBranches {
+		hash = d.commit.Hash
+		if d.remote.Name != "" {
+			lb, _ := m.selectedBranch()
+			cmp = branchCompare{branch: lb.Name, remote: d.remote.Name, unrelated: d.remote.HistoriesUnrelated}
+		}
+		m.diffDetailRepo = m.currentRepo()
+	}
+	if cmp != m.diffCompare {
+		m.diffCompare = cmp
+		m.diffCompareReverse = false
+		m.diffNeedsRefresh = true
+	}
+	if hash != m.diffCommit {
+		m.diffCommit = hash
+		m.diffNeedsRefresh = true
+	}
+}
+
+// showCommitDiff loads git show for diffCommit. Hunk operations do not apply to
+// a commit, so no hunks are parsed.
+func (m *model) showCommitDiff(repo string) {
+	m.diffTarget = repo + "\x00" + m.diffCommit
+	m.diffHunkSelected = false
+	out, err := runGitPatch(repo, "show", "--stat", "--patch", m.diffCommit)
+	if err != nil {
+		m.diffErr = err
+		m.diffContent = fmt.Sprintf("git show failed: %v", err)
+		return
+	}
+	m.diffRaw = out
+	m.renderDiffContent()
+}
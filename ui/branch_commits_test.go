@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestBranchUnpushedCommitsShowInDiff(t *testing.T) {
+	repo, _ := cloneWithBareOrigin(t)
+	runGitT(t, repo, "checkout", "-q", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(repo, "notes.txt"), []byte("remember\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitT(t, repo, "add", "notes.txt")
+	runGitT(t, repo, "commit", "-q", "-m", "add notes")
+	runGitT(t, repo, "commit", "-q", "--allow-empty", "-m", "empty follow-up")
+	notes := strings.TrimSpace(runGitT(t, repo, "rev-parse", "HEAD~1"))
+	// The commit diff must stay plain whatever the user's diff format settings.
+	runGitT(t, repo, "config", "color.ui", "always")
+	runGitT(t, repo, "config", "diff.noprefix", "true")
+
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.focus = paneBranches
+	m.syncViewports()
+
+	for i, lb := range m.branchRows {
+		if lb.Name == "feature" {
+			m.branchTable.SetCursor(i)
+		}
+	}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	if _, _, handled := m.handleCommandKey(space); !handled {
+		t.Fatal("Space should expand the branch")
+	}
+	rows := m.branchTable.Rows()
+	at := m.branchTable.Cursor()
+	if len(rows) < at+3 || rows[at+1][0] != "  empty follow-up" || rows[at+2][0] != "  add notes" || rows[at+2][3] != "u" {
+		t.Fatalf("rows = %q", rows)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.diffCommit != notes {
+		t.Fatalf("diffCommit = %q, want %q", m.diffCommit, notes)
+	}
+	if !strings.Contains(m.diffRaw, "add notes") || !strings.Contains(m.diffRaw, "+++ b/notes.txt\n@@ -0,0 +1 @@\n+remember") ||
+		strings.Contains(m.diffRaw, "\x1b[") || !strings.Contains(m.diffPaneBorderTitle(), "commit "+shortHash(notes)) {
+		t.Fatalf("diff:\n%s\ntitle %q", m.diffRaw, m.diffPaneBorderTitle())
+	}
+	if lb, ok := m.selectedBranch(); !ok || lb.Name != "feature" {
+		t.Fatalf("a commit row should select its branch, got %+v", lb)
+	}
+
+	// Focusing Diff to scroll the commit keeps it there.
+	m.focus = paneDiff
+	m.syncViewports()
+	m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.diffCommit != notes || !strings.Contains(m.diffRaw, "+remember") {
+		t.Fatalf("Diff focus should keep the commit; diffCommit %q diff:\n%s", m.diffCommit, m.diffRaw)
+	}
+
+	m.focus = paneStatus
+	m.syncViewports()
+	if m.diffCommit != "" || strings.Contains(m.diffRaw, "add notes") {
+		t.Fatal("focusing Status should return Diff to the working tree")
+	}
+
+	m.focus = paneBranches
+	m.syncViewports()
+	m.handleCommandKey(space)
+	if len(m.branchTable.Rows()) != len(rows)-2 || m.branchTable.Cursor() != at {
+		t.Fatalf("Space on a commit row should collapse to the branch row; cursor %d rows %q", m.branchTable.Cursor(), m.branchTable.Rows())
+	}
+}
+
+func TestBranchUnpushedCommitsCapped(t *testing.T) {
+	repo, _ := cloneWithBareOrigin(t)
+	for range branchCommitRowsMax + 3 {
+		runGitT(t, repo, "commit", "-q", "--allow-empty", "-m", "c")
+	}
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.focus = paneBranches
+	m.syncViewports()
+	m.branchTable.SetCursor(0)
+	m.handleCommandKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+
+	// The branch, its origin/main row, the capped commits, then the more row.
+	rows := m.branchTable.Rows()
+	if len(rows) != branchCommitRowsMax+3 || rows[len(rows)-1][0] != "  … 3 more" {
+		t.Fatalf("got %d rows, last %q; want %d commits then a more row", len(rows), rows[len(rows)-1], branchCommitRowsMax)
+	}
+}
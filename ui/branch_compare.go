@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// branchCompare is a local branch and the remote whose copy of it the Diff pane
+// compares it with.
+type branchCompare struct {
+	branch string
+	remote string
+	// unrelated histories have no merge base, so the two tips are diffed directly.
+	unrelated bool
+}
+
+// localLocation returns the branch's "local" location.
+func localLocation(lb scanner.LocalBranchRef) (scanner.BranchLocation, bool) {
+	for _, loc := range lb.Locations {
+		if loc.Name == "local" {
+			return loc, true
+		}
+	}
+	return scanner.BranchLocation{}, false
+}
+
+// differingRemotes returns the remote copies of the branch whose tip is not the
+// local tip, in the order the scan found them.
+func differingRemotes(lb scanner.LocalBranchRef) []scanner.BranchLocation {
+	local, ok := localLocation(lb)
+	if !ok || !local.Exists {
+		return nil
+	}
+	var out []scanner.BranchLocation
+	for _, loc := range lb.Locations {
+		if loc.Name != "local" && loc.Exists && loc.TipHash != local.TipHash {
+			out = append(out, loc)
+		}
+	}
+	return out
+}
+
+// sides returns the ref the comparison starts from and the one whose changes it
+// shows: the local branch by default, the remote copy when reversed.
+func (c branchCompare) sides(reverse bool) (from, to string) {
+	from, to = c.remote+"/"+c.branch, c.branch
+	if reverse {
+		from, to = to, from
+	}
+	return from, to
+}
+
+// label writes the comparison the way git diff takes it, e.g. origin/main...main.
+func (c branchCompare) label(reverse bool) string {
+	from, to := c.sides(reverse)
+	if c.unrelated {
+		return from + " " + to
+	}
+	return from + "..." + to
+}
+
+// fullRef qualifies a side returned by sides so a branch named like a remote
+// ref cannot be mistaken for it.
+func (c branchCompare) fullRef(side string) string {
+	if side == c.branch {
+		return "refs/heads/" + c.branch
+	}
+	return "refs/remotes/" + side
+}
+
+// toggleCompareDirection swaps which side of the remote comparison Diff shows.
+func (m *model) toggleCompareDirection() bool {
+	if m.focus != paneBranches || m.diffCompare.remote == "" {
+		return false
+	}
+	m.diffCompareReverse = !m.diffCompareReverse
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+	return true
+}
+
+// showCompareDiff loads git diff --stat --patch between the branch and its
+// remote copy: what one side changed since the merge base, under a line
+// counting the commits behind it. Hunk operations do not apply here.
+func (m *model) showCompareDiff(repo string) {
+	c, reverse := m.diffCompare, m.diffCompareReverse
+	m.diffTarget = fmt.Sprintf("%s\x00%s\x00%s\x00%t", repo, c.branch, c.remote, reverse)
+	m.diffHunkSelected = false
+	from, to := c.sides(reverse)
+	fromRef, toRef := c.fullRef(from), c.fullRef(to)
+	args := []string{"--stat", "--patch", fromRef + "..." + toRef}
+	if c.unrelated {
+		args = []string{"--stat", "--patch", fromRef, toRef}
+	}
+	out, err := runGitPatch(repo, "diff", args...)
+	if err != nil {
+		m.diffErr = err
+		m.diffContent = fmt.Sprintf("git diff failed: %v", err)
+		return
+	}
+	header := "git diff " + c.label(reverse)
+	if n, err := runGitInRepo(repo, "rev-list", "--count", fromRef+".."+toRef); err == nil {
+		header = fmt.Sprintf("%s: %s commit(s) on %s that %s does not have (R: other side)", header, n, to, from)
+	}
+	if strings.TrimSpace(out) == "" {
+		out = "(no file changes)"
+	}
+	m.diffRaw = header + "\n\n" + out
+	m.renderDiffContent()
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestBranchCompareWithRemote(t *testing.T) {
+	repo, origin := cloneWithBareOrigin(t)
+	commitFile := func(dir, name, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(msg+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGitT(t, dir, "add", name)
+		runGitT(t, dir, "commit", "-q", "-m", msg)
+	}
+	other := filepath.Join(filepath.Dir(origin), "other")
+	runGitT(t, filepath.Dir(origin), "clone", "-q", origin, other)
+	commitFile(other, "theirs.txt", "their change")
+	runGitT(t, other, "push", "-q", "origin", "main")
+	runGitT(t, repo, "fetch", "-q")
+	commitFile(repo, "ours.txt", "our change")
+	runGitT(t, repo, "config", "color.ui", "always")
+	runGitT(t, repo, "config", "diff.mnemonicPrefix", "true")
+
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.focus = paneBranches
+	m.syncViewports()
+
+	for i, lb := range m.branchRows {
+		if lb.Name == "main" {
+			m.branchTable.SetCursor(i)
+		}
+	}
+	m.handleCommandKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	at := m.branchTable.Cursor()
+	if rows := m.branchTable.Rows(); len(rows) < at+3 || rows[at+1][0] != "  ↔ origin/main" || rows[at+1][3] != "origin +1-1" {
+		t.Fatalf("rows = %q", rows)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.diffCompare != (branchCompare{branch: "main", remote: "origin"}) {
+		t.Fatalf("diffCompare = %+v", m.diffCompare)
+	}
+	if !strings.HasPrefix(m.diffRaw, "git diff origin/main...main: 1 commit(s) on main that origin/main does not have") ||
+		!strings.Contains(m.diffRaw, "ours.txt | 1 +") || strings.Contains(m.diffRaw, "theirs.txt") {
+		t.Fatalf("local side:\n%s", m.diffRaw)
+	}
+
+	m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if !strings.Contains(m.diffRaw, "+++ b/theirs.txt") || !strings.Contains(m.diffRaw, "+their change") ||
+		strings.Contains(m.diffRaw, "\x1b[") || strings.Contains(m.diffRaw, "ours.txt") ||
+		!strings.Contains(m.diffPaneBorderTitle(), "main...origin/main") {
+		t.Fatalf("remote side:\n%s\ntitle %q", m.diffRaw, m.diffPaneBorderTitle())
+	}
+	m.focus = paneDiff
+	m.syncViewports()
+	if m.diffCompare.remote != "origin" || !strings.Contains(m.diffRaw, "+their change") {
+		t.Fatal("Diff focus should keep the comparison")
+	}
+	m.focus = paneBranches
+	m.syncViewports()
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.diffCompare != (branchCompare{}) || m.diffCommit == "" {
+		t.Fatalf("the unpushed commit row should replace the comparison; compare %+v commit %q", m.diffCompare, m.diffCommit)
+	}
+}
@@ -105,3 +105,49 @@ func TestBranchRemoteSummaryFromLocations(t *testing.T) {
 		})
 	}
 }
+
+func TestBranchRemoteSummaryUpstreamGone(t *testing.T) {
+	t.Parallel()
+
+	locs := []scanner.BranchLocation{
+		{Name: "local", Exists: true, TipHash: "aaa111"},
+		{Name: "origin", Exists: false},
+	}
+
+	tests := []struct {
+		name string
+		lb   scanner.LocalBranchRef
+		want string
+	}{
+		{
+			name: "local only",
+			lb:   scanner.LocalBranchRef{Name: "wip", Locations: locs},
+			want: "origin: missing",
+		},
+		{
+			name: "gone and merged",
+			lb:   scanner.LocalBranchRef{Name: "done", Locations: locs, UpstreamGone: true, MergedInto: "origin/main"},
+			want: "gone, merged into origin/main; origin: missing",
+		},
+		{
+			name: "gone with unique commits",
+			lb:   scanner.LocalBranchRef{Name: "abandoned", Locations: locs, UpstreamGone: true, UnmergedCount: 3},
+			want: "gone, 3 unmerged; origin: missing",
+		},
+		{
+			name: "gone without default branch",
+			lb:   scanner.LocalBranchRef{Name: "unknown", UpstreamGone: true},
+			want: "gone",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := branchRemoteSummary(tt.lb); got != tt.want {
+				t.Fatalf("branchRemoteSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+const (
+	// commitModalMaxStaged caps how many staged paths the commit overlay lists.
+	commitModalMaxStaged = 8
+	// commitMessageRows is the height of the inline message editor.
+	commitMessageRows = 6
+)
+
+// commitEditorDoneMsg is sent when $GIT_EDITOR exits after editing the draft message in path.
+type commitEditorDoneMsg struct {
+	path string
+	err  error
+}
+
+// newCommitMessageInput builds the multi-line commit message editor.
+func newCommitMessageInput() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "Commit message"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetHeight(commitMessageRows)
+	return ta
+}
+
+// stagedEntries returns the porcelain entries with index changes for the selected repository.
+func (m *model) stagedEntries() []scanner.PorcelainEntry {
+	st, ok := m.repositories.Get(m.currentRepo())
+	if !ok {
+		return nil
+	}
+	var out []scanner.PorcelainEntry
+	for _, e := range st.Porcelain.Entries {
+		if e.Staging != ' ' && e.Staging != '?' && e.Staging != '!' {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// openCommit shows the commit overlay for the selected repository.
+func (m *model) openCommit() (tea.Cmd, bool) {
+	if m.err != nil || m.currentRepo() == "" {
+		return nil, false
+	}
+	m.commitOpen = true
+	m.commitAmend = false
+	m.commitErr = ""
+	m.commitInput = newCommitMessageInput()
+	return m.commitInput.Focus(), true
+}
+
+// handleCommitKey processes keys while the commit overlay is open. Keys not bound
+// here edit the message.
+func (m *model) handleCommitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.commitOpen = false
+		return m, nil
+	case "ctrl+s":
+		m.runCommit()
+		return m, nil
+	case "ctrl+o":
+		m.toggleCommitAmend()
+		return m, nil
+	case "ctrl+g":
+		return m, m.editCommitMessage()
+	}
+	var cmd tea.Cmd
+	m.commitInput, cmd = m.commitInput.Update(msg)
+	return m, cmd
+}
+
+// toggleCommitAmend switches amend mode. Turning it on with an empty message loads
+// the last commit's message, as `git commit --amend` does; turning it off again
+// clears that message if it was not edited.
+func (m *model) toggleCommitAmend() {
+	m.commitAmend = !m.commitAmend
+	head, err := runGitInRepo(m.currentRepo(), "log", "-1", "--format=%B")
+	if err != nil {
+		if m.commitAmend {
+			m.commitAmend = false
+			m.commitErr = fmt.Sprintf("nothing to amend: %v", err)
+		}
+		return
+	}
+	m.commitErr = ""
+	switch {
+	case m.commitAmend && strings.TrimSpace(m.commitInput.Value()) == "":
+		m.commitInput.SetValue(head)
+	case !m.commitAmend && strings.TrimSpace(m.commitInput.Value()) == head:
+		m.commitInput.Reset()
+	}
+}
+
+// runCommit runs git commit with the drafted message. Output goes to the Log pane;
+// on failure the overlay stays open with git's message.
+func (m *model) runCommit() {
+	repo := m.currentRepo()
+	message := strings.TrimSpace(m.commitInput.Value())
+	if message == "" {
+		m.commitErr = "The commit message is empty."
+		return
+	}
+	args := []string{"commit", "-m", message}
+	if m.commitAmend {
+		args = append(args, "--amend")
+	}
+	out, err := runGitInRepo(repo, args...)
+	if err != nil {
+		log.Printf("git: %v", err)
+		m.commitErr = err.Error()
+		return
+	}
+	for _, line := range strings.Split(out, "\n") {
+		log.Printf("git commit: %s", line)
+	}
+	m.commitOpen = false
+	m.refreshRepoStatusAfterGit()
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+}
+
+// editCommitMessage hands the terminal to $GIT_EDITOR (resolved the way git does,
+// via `git var GIT_EDITOR`) to edit the draft in the repository's COMMIT_EDITMSG.
+// The edited text comes back to the overlay through commitEditorDoneMsg.
+func (m *model) editCommitMessage() tea.Cmd {
+	repo := m.currentRepo()
+	editor, err := runGitInRepo(repo, "var", "GIT_EDITOR")
+	if err != nil {
+		m.commitErr = fmt.Sprintf("no editor: %v", err)
+		return nil
+	}
+	rel, err := runGitInRepo(repo, "rev-parse", "--git-path", "COMMIT_EDITMSG")
+	if err != nil {
+		m.commitErr = err.Error()
+		return nil
+	}
+	path := rel
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repo, rel)
+	}
+	if err := os.WriteFile(path, []byte(m.commitEditorTemplate()), 0o644); err != nil {
+		m.commitErr = err.Error()
+		return nil
+	}
+	// Same invocation as git: the editor string may carry its own arguments.
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	cmd.Dir = repo
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return commitEditorDoneMsg{path: path, err: err}
+	})
+}
+
+// commitEditorTemplate is the draft message followed by git-style comment lines
+// naming the staged paths.
+func (m *model) commitEditorTemplate() string {
+	var b strings.Builder
+	b.WriteString(m.commitInput.Value())
+	b.WriteString("\n\n# Lines starting with '#' are ignored. Save and quit to return to dirtygit.\n")
+	if m.commitAmend {
+		b.WriteString("# Amending the last commit.\n")
+	}
+	b.WriteString("#\n# Changes to be committed:\n")
+	for _, e := range m.stagedEntries() {
+		fmt.Fprintf(&b, "#\t%s\n", stagedEntryLabel(e))
+	}
+	return b.String()
+}
+
+// stripCommitComments drops '#' comment lines and surrounding blank lines from an
+// edited message.
+func stripCommitComments(s string) string {
+	var keep []string
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			keep = append(keep, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(keep, "\n"))
+}
+
+// handleCommitEditorDone loads the message written by $GIT_EDITOR into the overlay.
+func (m *model) handleCommitEditorDone(msg commitEditorDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		log.Printf("editor: %v", msg.err)
+		m.commitErr = fmt.Sprintf("editor: %v", msg.err)
+		return m, nil
+	}
+	b, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.commitErr = err.Error()
+		return m, nil
+	}
+	m.commitErr = ""
+	m.commitInput.SetValue(stripCommitComments(string(b)))
+	return m, nil
+}
+
+// stagedEntryLabel formats a staged path with its index status, e.g. "M  main.go".
+func stagedEntryLabel(e scanner.PorcelainEntry) string {
+	if e.OriginalPath != "" {
+		return fmt.Sprintf("%c  %s → %s", e.Staging, e.OriginalPath, e.Path)
+	}
+	return fmt.Sprintf("%c  %s", e.Staging, e.Path)
+}
+
+// renderCommitOverlay shows the staged paths, the message editor, and the amend toggle.
+func (m *model) renderCommitOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+
+	title := "Commit staged changes"
+	if m.commitAmend {
+		title = "Amend the last commit"
+	}
+	staged := m.stagedEntries()
+	parts := []string{
+		styleBold.Render(title), "",
+		styleDim.Render(truncateASCII(m.currentRepo(), innerW)), "",
+		fmt.Sprintf("Staged (%d)", len(staged)),
+	}
+	for i, e := range staged {
+		if i == commitModalMaxStaged {
+			parts = append(parts, styleDim.Render(fmt.Sprintf("  … and %d more", len(staged)-i)))
+			break
+		}
+		parts = append(parts, "  "+truncateASCII(stagedEntryLabel(e), innerW-2))
+	}
+	if len(staged) == 0 {
+		note := "  Nothing staged; stage paths with a first."
+		if m.commitAmend {
+			note = "  Nothing staged; only the message will change."
+		}
+		parts = append(parts, styleDim.Render(note))
+	}
+
+	m.commitInput.SetWidth(innerW)
+	amend := "[ ] Amend the last commit"
+	if m.commitAmend {
+		amend = "[x] Amend the last commit"
+	}
+	parts = append(parts, "", m.commitInput.View(), "", amend)
+	if m.commitErr != "" {
+		parts = append(parts, "", warnBlock(innerW).Render(m.commitErr))
+	}
+	footer := styleDim.Render("Ctrl+S commit · Ctrl+O amend · Ctrl+G edit in $GIT_EDITOR · Esc cancel")
+	parts = append(parts, "", footer)
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(strings.Join(parts, "\n")))
+}
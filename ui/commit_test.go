@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestCommitOverlayCommitsAndAmends(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "r")
+	runGitT(t, filepath.Dir(repo), "init", "-q", "-b", "main", repo)
+	// The overlay runs git commit without the helpers' author environment.
+	runGitT(t, repo, "config", "user.email", "u@x")
+	runGitT(t, repo, "config", "user.name", "u")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitT(t, repo, "add", "a.txt")
+
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 100
+	m.height = 30
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	prevLog := log.Writer()
+	log.SetOutput(m.logBuf)
+	defer log.SetOutput(prevLog)
+
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}}); !handled || !m.commitOpen {
+		t.Fatal("c should open the commit overlay")
+	}
+	if v := m.View(); !strings.Contains(v, "A  a.txt") || !strings.Contains(v, "Staged (1)") {
+		t.Fatalf("overlay should list the staged file:\n%s", v)
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !m.commitOpen || m.commitErr == "" {
+		t.Fatal("an empty message should keep the overlay open with an error")
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("first")})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("body")})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.commitOpen {
+		t.Fatalf("commit should close the overlay: %s", m.commitErr)
+	}
+	if got := strings.TrimSpace(runGitT(t, repo, "log", "-1", "--format=%B")); got != "first\nbody" {
+		t.Fatalf("message = %q", got)
+	}
+	if !strings.Contains(m.logBuf.String(), "git commit: [main (root-commit)") {
+		t.Fatalf("log should hold git's output: %q", m.logBuf.String())
+	}
+	if _, ok := m.repositories.Get(repo); ok {
+		t.Fatal("clean repo should drop out of the list after the refresh")
+	}
+
+	// Amend loads the previous message; the commit count stays at one.
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.openCommit()
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if !m.commitAmend || m.commitInput.Value() != "first\nbody" {
+		t.Fatalf("amend should prefill the last message, got %q", m.commitInput.Value())
+	}
+	m.commitInput.SetValue("reworded")
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if got := strings.TrimSpace(runGitT(t, repo, "log", "--format=%s")); got != "reworded" {
+		t.Fatalf("log after amend = %q", got)
+	}
+}
+
+func TestCommitEditorRoundTrip(t *testing.T) {
+	m := newTestModel()
+	m.repositories.AddResult("/r", scanner.RepoStatus{Porcelain: scanner.PorcelainStatus{Entries: []scanner.PorcelainEntry{
+		{Staging: 'M', Worktree: ' ', Path: "main.go"},
+		{Staging: ' ', Worktree: 'M', Path: "unstaged.go"},
+	}}})
+	m.repoList = []string{"/r"}
+	m.openCommit()
+	m.commitInput.SetValue("draft")
+	tmpl := m.commitEditorTemplate()
+	if !strings.HasPrefix(tmpl, "draft\n") || !strings.Contains(tmpl, "#\tM  main.go") || strings.Contains(tmpl, "unstaged.go") {
+		t.Fatalf("template = %q", tmpl)
+	}
+
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	edited := "subject\n\n  body line  \n# comment\n" + tmpl[len("draft\n"):]
+	if err := os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(commitEditorDoneMsg{path: path})
+	if got := m.commitInput.Value(); got != "subject\n\n  body line" {
+		t.Fatalf("edited message = %q", got)
+	}
+}
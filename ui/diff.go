@@ -19,7 +19,24 @@ func (m *model) diffModeLabel() string {
 // plus Worktree and Staged with the active diff mode emphasized.
 func (m *model) diffPaneBorderTitle() string {
 	// Not lipgloss 214: that matches the focused-pane border accent (see view_test).
-	return diffPaneTopBorderLabel(m.focus == paneDiff, m.diffMode == diffModeWorktree)
+	title := diffPaneTopBorderLabel(m.focus == paneDiff, m.diffMode == diffModeWorktree)
+	if m.diffStash != "" {
+		return title + styleDim.Render(" · "+m.diffStashRef())
+	}
+	if m.diffCommit != "" {
+		return title + styleDim.Render(" · commit "+shortHash(m.diffCommit))
+	}
+	if m.diffCompare.remote != "" {
+		return title + styleDim.Render(" · "+m.diffCompare.label(m.diffCompareReverse))
+	}
+	if m.diffHunkSelected && len(m.diffHunks) > 0 {
+		title += styleDim.Render(fmt.Sprintf(" · hunk %d/%d", m.diffHunkCursor+1, len(m.diffHunks)))
+		if m.diffLineMode {
+			lo, hi := m.selectedLineRange()
+			title += styleDim.Render(fmt.Sprintf(" · lines %d-%d", lo, hi))
+		}
+	}
+	return title
 }
 
 // refreshDiffContent reloads the visible diff text when needed.
@@ -29,14 +46,37 @@ func (m *model) refreshDiffContent() {
 	}
 	m.diffNeedsRefresh = false
 	m.diffErr = nil
+	m.diffRaw = ""
+	m.diffHunks = nil
+	m.diffLineMode = false
 
 	repo := m.currentRepo()
 	if repo == "" {
 		m.diffContent = "(select a repository to view diffs)"
 		return
 	}
+	if m.diffStash != "" {
+		m.showStashDiff(repo)
+		return
+	}
+	if m.diffCommit != "" {
+		m.showCommitDiff(repo)
+		return
+	}
+	if m.diffCompare.remote != "" {
+		m.showCompareDiff(repo)
+		return
+	}
 
 	path := m.selectedStatusPath()
+	// The hunk cursor survives refreshes of the same diff (e.g. after staging a
+	// hunk, the next one moves up into its place) but not a change of target.
+	target := fmt.Sprintf("%s\x00%s\x00%d", repo, path, m.diffMode)
+	if target != m.diffTarget {
+		m.diffTarget = target
+		m.diffHunkSelected = false
+		m.diffHunkCursor = 0
+	}
 	out, err := gitDiff(repo, path, m.diffMode == diffModeStaged)
 	if err != nil {
 		m.diffErr = err
@@ -45,62 +85,131 @@ func (m *model) refreshDiffContent() {
 			return
 		}
 	}
-	if strings.TrimSpace(out) == "" {
-		target := "all changed files"
+	// A selected untracked entry, or those under a directory row, follow the
+	// tracked diff as new-file diffs. They are left out of the hunks: a stages
+	// them whole from Status.
+	untracked := ""
+	if m.diffMode == diffModeWorktree {
+		if entries := m.selectedUntrackedEntries(repo, path); len(entries) > 0 {
+			untracked = untrackedDiff(repo, entries)
+		}
+	}
+	if strings.TrimSpace(out) == "" && untracked == "" {
+		what := "all changed files"
 		if path != "" {
-			target = path
+			what = path
 		}
-		m.diffContent = fmt.Sprintf("(no %s diff for %s)", strings.ToLower(m.diffModeLabel()), target)
+		m.diffContent = fmt.Sprintf("(no %s diff for %s)", strings.ToLower(m.diffModeLabel()), what)
+		m.diffHunkSelected = false
+		return
+	}
+	m.diffHunks = parseDiffHunks(out)
+	if strings.TrimSpace(out) == "" {
+		out = ""
+	} else if untracked != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	m.diffRaw = out + untracked
+	if len(m.diffHunks) == 0 {
+		m.diffHunkSelected = false
+	}
+	m.diffHunkCursor = min(m.diffHunkCursor, max(0, len(m.diffHunks)-1))
+	m.renderDiffContent()
+}
+
+// renderDiffContent styles the raw diff, highlighting the selected hunk's "@@"
+// line and any line selection inside it. The styled diff is kept between calls,
+// so moving the hunk cursor does not highlight the diff again.
+func (m *model) renderDiffContent() {
+	if m.diffRaw == "" {
 		return
 	}
-	m.diffContent = styleDiffContent(out)
+	opts := m.diffStyleOptions()
+	if m.diffStyledRaw != m.diffRaw || m.diffStyledOpts != opts {
+		m.diffStyled = styleDiff(m.diffRaw, opts)
+		m.diffStyledRaw, m.diffStyledOpts = m.diffRaw, opts
+	}
+	h, ok := m.selectedHunk()
+	if !ok {
+		m.diffContent = m.diffStyled
+		return
+	}
+	lines := strings.Split(m.diffRaw, "\n")
+	styled := strings.Split(m.diffStyled, "\n")
+	styled[h.start] = styleSelRowFocused.Render(lines[h.start])
+	if m.diffLineMode {
+		lo, hi := m.selectedLineRange()
+		for i := lo; i <= hi; i++ {
+			style := styleSelRowBlurred
+			if i == m.diffLineCursor {
+				style = styleSelRowFocused
+			}
+			styled[h.start+i] = style.Render(lines[h.start+i])
+		}
+	}
+	m.diffContent = strings.Join(styled, "\n")
 }
 
 // styleDiffContent colorizes git diff output for terminal display.
 func styleDiffContent(raw string) string {
-	if raw == "" {
-		return ""
-	}
+	return styleDiff(raw, diffStyleOptions{})
+}
 
-	lines := strings.Split(raw, "\n")
-	for i, line := range lines {
-		switch {
-		case strings.HasPrefix(line, "diff --git"):
-			lines[i] = diffStyleHeader.Render(line)
-		case strings.HasPrefix(line, "@@"):
-			lines[i] = diffStyleHunk.Render(line)
-		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
-			lines[i] = diffStyleFile.Render(line)
-		case strings.HasPrefix(line, "+"):
-			lines[i] = diffStyleAdded.Render(line)
-		case strings.HasPrefix(line, "-"):
-			lines[i] = diffStyleDeleted.Render(line)
-		case strings.HasPrefix(line, "index "),
-			strings.HasPrefix(line, "new file mode "),
-			strings.HasPrefix(line, "deleted file mode "),
-			strings.HasPrefix(line, "similarity index "),
-			strings.HasPrefix(line, "rename from "),
-			strings.HasPrefix(line, "rename to "):
-			lines[i] = diffStyleMeta.Render(line)
-		}
+// styleDiffLine colours one line of git diff output by its prefix.
+func styleDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "diff --git"), strings.HasPrefix(line, "commit "):
+		return diffStyleHeader.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return diffStyleHunk.Render(line)
+	case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+		return diffStyleFile.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return diffStyleAdded.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return diffStyleDeleted.Render(line)
+	case strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "new file mode "),
+		strings.HasPrefix(line, "deleted file mode "),
+		strings.HasPrefix(line, "similarity index "),
+		strings.HasPrefix(line, "rename from "),
+		strings.HasPrefix(line, "rename to "):
+		return diffStyleMeta.Render(line)
 	}
-	return strings.Join(lines, "\n")
+	return line
 }
 
-// gitDiff runs git diff for a repository and optional file path.
+// gitDiff runs git diff for a repository and optional file path. Its output is
+// also the source of the hunk patches fed to git apply (see [runGitPatch]).
 func gitDiff(repo, path string, staged bool) (string, error) {
-	args := []string{"diff"}
+	var args []string
 	if staged {
 		args = append(args, "--cached")
 	}
 	if path != "" {
 		args = append(args, "--", path)
 	}
-	cmd := exec.Command("git", args...)
-	cmd.Dir = repo
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-	err := cmd.Run()
+	return runGitPatch(repo, "diff", args...)
+}
+
+// plainPatchFlags pin patch output against user settings that change its
+// format: color.ui or color.diff, diff.external, and diff.noprefix or
+// diff.mnemonicPrefix.
+var plainPatchFlags = []string{"--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/"}
+
+// runGitPatch runs the git subcommand cmd ("diff", "show", "stash show") in
+// repo with plainPatchFlags and then args. It returns stdout alone, so warnings
+// such as core.autocrlf's stay out of hunk bodies; stderr is kept for the error.
+func runGitPatch(repo, cmd string, args ...string) (string, error) {
+	full := append(strings.Fields(cmd), plainPatchFlags...)
+	c := exec.Command("git", append(full, args...)...)
+	c.Dir = repo
+	var out, stderr bytes.Buffer
+	c.Stdout = &out
+	c.Stderr = &stderr
+	err := c.Run()
+	if msg := strings.TrimSpace(stderr.String()); err != nil && msg != "" {
+		err = fmt.Errorf("%w: %s", err, msg)
+	}
 	return out.String(), err
 }
@@ -0,0 +1,363 @@
+package ui
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// diffHighlightMaxBytes is the largest diff that gets syntax highlighting;
+// bigger ones keep the plain line colours.
+const diffHighlightMaxBytes = 256 << 10
+
+// diffChromaStyle colours the tokens of highlighted diff bodies.
+var diffChromaStyle = styles.Get("monokai")
+
+// diffStyleOptions selects the optional parts of diff rendering.
+type diffStyleOptions struct {
+	// highlight colours hunk bodies by the language of the file's extension.
+	highlight bool
+	// wordDiff emphasises the changed words of paired -/+ lines.
+	wordDiff bool
+}
+
+// diffStyleOptions returns the diff rendering the config asks for. Without
+// colour (NO_COLOR, or output that is not a terminal) neither can show, so
+// both are off and the Diff pane shows git's plain text.
+func (m *model) diffStyleOptions() diffStyleOptions {
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		return diffStyleOptions{}
+	}
+	return diffStyleOptions{highlight: m.config.DiffHighlight(), wordDiff: m.config.DiffWordDiff()}
+}
+
+// styleDiff colourises git diff output line by line, as styleDiffContent does,
+// and as opts allow highlights hunk bodies and emphasises changed words. The
+// result has as many lines as raw.
+func styleDiff(raw string, opts diffStyleOptions) string {
+	if raw == "" {
+		return ""
+	}
+	if len(raw) > diffHighlightMaxBytes {
+		opts.highlight = false
+	}
+	lines := strings.Split(raw, "\n")
+	out := make([]string, len(lines))
+	var lexer chroma.Lexer
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if oldN, newN, ok := hunkLineCounts(line); ok {
+			out[i] = diffStyleHunk.Render(line)
+			end := hunkBodyEnd(lines, i+1, oldN, newN)
+			styleHunkBody(lines[i+1:end], out[i+1:end], lexer, opts)
+			i = end - 1
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "diff --git"), strings.HasPrefix(line, "commit "):
+			lexer = nil
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+			// The new path wins, so a renamed file takes its new language.
+			if p := line[4:]; opts.highlight && p != "/dev/null" {
+				lexer = diffLexer(strings.Trim(p, `"`))
+			}
+		}
+		out[i] = styleDiffLine(line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// hunkLineCounts reads the old and new line counts from a "@@ -a,b +c,d @@"
+// line; a missing count is 1. Combined diffs ("@@@") do not match.
+func hunkLineCounts(line string) (oldN, newN int, ok bool) {
+	rest, ok := strings.CutPrefix(line, "@@ -")
+	if !ok {
+		return 0, 0, false
+	}
+	ranges, _, ok := strings.Cut(rest, " @@")
+	if !ok {
+		return 0, 0, false
+	}
+	oldR, newR, ok := strings.Cut(ranges, " +")
+	if !ok {
+		return 0, 0, false
+	}
+	count := func(r string) (int, bool) {
+		_, n, found := strings.Cut(r, ",")
+		if !found {
+			return 1, true
+		}
+		v, err := strconv.Atoi(n)
+		return v, err == nil
+	}
+	oldN, okOld := count(oldR)
+	newN, okNew := count(newR)
+	return oldN, newN, okOld && okNew
+}
+
+// hunkBodyEnd returns the index just past the hunk body starting at lines[start],
+// counting lines against the hunk header so a deleted "--- x" line stays body.
+func hunkBodyEnd(lines []string, start, oldN, newN int) int {
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			return i
+		}
+		if line[0] == '\\' {
+			continue
+		}
+		if oldN <= 0 && newN <= 0 {
+			return i
+		}
+		switch line[0] {
+		case ' ':
+			oldN--
+			newN--
+		case '-':
+			oldN--
+		case '+':
+			newN--
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// styleHunkBody renders the body lines of one hunk into out.
+func styleHunkBody(body, out []string, lexer chroma.Lexer, opts diffStyleOptions) {
+	if lexer == nil && !opts.wordDiff {
+		for i, line := range body {
+			out[i] = renderDiffBodyLine(line, nil, nil, false)
+		}
+		return
+	}
+	var tokens [][]chroma.Token
+	if lexer != nil {
+		tokens = highlightHunk(body, lexer)
+	}
+	var changed [][]bool
+	if opts.wordDiff {
+		changed = wordDiffHunk(body)
+	}
+	for i, line := range body {
+		var lineTokens []chroma.Token
+		if tokens != nil {
+			lineTokens = tokens[i]
+		}
+		var lineChanged []bool
+		if changed != nil {
+			lineChanged = changed[i]
+		}
+		out[i] = renderDiffBodyLine(line, lineTokens, lineChanged, lexer != nil)
+	}
+}
+
+// highlightHunk tokenises the old side (context and deleted lines) and the new
+// side (context and added lines) of a hunk as two pieces of source, so strings
+// and comments spanning lines colour correctly, and returns each body line's
+// tokens. Context lines take the new side's.
+func highlightHunk(body []string, lexer chroma.Lexer) [][]chroma.Token {
+	var sides [2][]string
+	var at [2][]int
+	for i, line := range body {
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case ' ':
+			sides[0], at[0] = append(sides[0], line[1:]), append(at[0], i)
+			sides[1], at[1] = append(sides[1], line[1:]), append(at[1], i)
+		case '-':
+			sides[0], at[0] = append(sides[0], line[1:]), append(at[0], i)
+		case '+':
+			sides[1], at[1] = append(sides[1], line[1:]), append(at[1], i)
+		}
+	}
+	out := make([][]chroma.Token, len(body))
+	for s := range sides {
+		for k, toks := range highlightLines(lexer, sides[s]) {
+			if toks != nil {
+				out[at[s][k]] = toks
+			}
+		}
+	}
+	return out
+}
+
+// highlightLines tokenises lines as one source text and splits the tokens back
+// into lines. A line whose tokens do not spell it exactly is left nil (plain).
+func highlightLines(lexer chroma.Lexer, lines []string) [][]chroma.Token {
+	out := make([][]chroma.Token, len(lines))
+	if len(lines) == 0 {
+		return out
+	}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, strings.Join(lines, "\n"))
+	if err != nil {
+		return out
+	}
+	split := chroma.SplitTokensIntoLines(it.Tokens())
+	for k := range min(len(lines), len(split)) {
+		toks := make([]chroma.Token, 0, len(split[k]))
+		var text strings.Builder
+		for _, t := range split[k] {
+			t.Value = strings.TrimSuffix(t.Value, "\n")
+			if t.Value != "" {
+				toks = append(toks, t)
+				text.WriteString(t.Value)
+			}
+		}
+		if text.String() == lines[k] {
+			out[k] = toks
+		}
+	}
+	return out
+}
+
+// diffSegment is a run of a body line drawn in one style.
+type diffSegment struct {
+	text     string
+	fg       lipgloss.Color
+	bold     bool
+	emphasis bool
+}
+
+// renderDiffBodyLine styles one hunk body line by its prefix alone, so a deleted
+// "--- x" line is not taken for a file header. Syntax colours from tokens
+// replace the green/red foreground, with a tinted background keeping -/+ lines
+// apart; bytes marked in changed get a stronger background. Without tokens the
+// line keeps its plain colour.
+func renderDiffBodyLine(line string, tokens []chroma.Token, changed []bool, highlighted bool) string {
+	if line == "" || line[0] == '\\' {
+		return line
+	}
+	if tokens == nil && changed == nil && !highlighted {
+		switch line[0] {
+		case '+':
+			return diffStyleAdded.Render(line)
+		case '-':
+			return diffStyleDeleted.Render(line)
+		}
+		return line
+	}
+	prefix, content := line[:1], line[1:]
+	var fg, bg, emphasisBg lipgloss.Color
+	switch prefix {
+	case "+":
+		fg, bg, emphasisBg = diffColorAdded, diffBgAdded, diffBgAddedEmphasis
+	case "-":
+		fg, bg, emphasisBg = diffColorDeleted, diffBgDeleted, diffBgDeletedEmphasis
+	}
+	if !highlighted {
+		bg = ""
+	}
+	if tokens == nil {
+		tokens = []chroma.Token{{Type: chroma.None, Value: content}}
+	}
+
+	// Adjacent tokens often share a colour; merging them keeps the escapes down.
+	segs := []diffSegment{{text: prefix, fg: fg}}
+	off := 0
+	for _, t := range tokens {
+		seg := diffSegment{fg: fg}
+		if t.Type != chroma.None {
+			e := diffChromaStyle.Get(t.Type)
+			if e.Colour.IsSet() {
+				seg.fg = lipgloss.Color(e.Colour.String())
+			}
+			seg.bold = e.Bold == chroma.Yes
+		}
+		for start := 0; start < len(t.Value); {
+			seg.emphasis = byteChanged(changed, off+start)
+			end := start + 1
+			for end < len(t.Value) && byteChanged(changed, off+end) == seg.emphasis {
+				end++
+			}
+			seg.text = t.Value[start:end]
+			if last := &segs[len(segs)-1]; len(segs) > 1 && last.fg == seg.fg && last.bold == seg.bold && last.emphasis == seg.emphasis {
+				last.text += seg.text
+			} else {
+				segs = append(segs, seg)
+			}
+			start = end
+		}
+		off += len(t.Value)
+	}
+
+	var b strings.Builder
+	for _, seg := range segs {
+		st := lipgloss.NewStyle().Bold(seg.bold)
+		if seg.fg != "" {
+			st = st.Foreground(diffProfileColor(seg.fg))
+		}
+		if segBg := bg; seg.emphasis || segBg != "" {
+			if seg.emphasis {
+				segBg = emphasisBg
+			}
+			st = st.Background(diffProfileColor(segBg))
+		}
+		b.WriteString(st.Render(seg.text))
+	}
+	return b.String()
+}
+
+// byteChanged reports whether byte i is marked changed.
+func byteChanged(changed []bool, i int) bool {
+	return i < len(changed) && changed[i]
+}
+
+// diffLexer returns the lexer for a file's extension, or for its whole name
+// when it has none (Makefile, Dockerfile). lexers.Match tries every lexer's
+// globs, so results are cached.
+func diffLexer(path string) chroma.Lexer {
+	name := filepath.Base(path)
+	if ext := filepath.Ext(name); ext != "" {
+		name = "file" + ext
+	}
+	if v, ok := diffLexers.Load(name); ok {
+		l, _ := v.(chroma.Lexer)
+		return l
+	}
+	l := lexers.Match(name)
+	diffLexers.Store(name, l)
+	return l
+}
+
+var diffLexers sync.Map
+
+// diffProfileColor converts a hex colour to the terminal's palette once:
+// lipgloss would otherwise search the 256-colour palette on every render.
+func diffProfileColor(c lipgloss.Color) lipgloss.Color {
+	p := lipgloss.ColorProfile()
+	if p == termenv.TrueColor || !strings.HasPrefix(string(c), "#") {
+		return c
+	}
+	key := diffProfileColorKey{p, c}
+	if v, ok := diffProfileColors.Load(key); ok {
+		return v.(lipgloss.Color)
+	}
+	out := c
+	switch tc := p.Color(string(c)).(type) {
+	case termenv.ANSI256Color:
+		out = lipgloss.Color(strconv.Itoa(int(tc)))
+	case termenv.ANSIColor:
+		out = lipgloss.Color(strconv.Itoa(int(tc)))
+	}
+	diffProfileColors.Store(key, out)
+	return out
+}
+
+type diffProfileColorKey struct {
+	profile termenv.Profile
+	color   lipgloss.Color
+}
+
+var diffProfileColors sync.Map
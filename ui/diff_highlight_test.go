@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+var ansiSGR = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func goDiffFixture(name string) string {
+	return strings.Join([]string{
+		"diff --git a/" + name + " b/" + name,
+		"index 111..222 100644",
+		"--- a/" + name,
+		"+++ b/" + name,
+		"@@ -1,3 +1,3 @@",
+		" package main",
+		"-func greet() string { return \"hello\" }",
+		"+func greet() string { return \"hi\" }",
+		" // done",
+		"",
+	}, "\n")
+}
+
+func TestStyleDiffKeepsLinesAndText(t *testing.T) {
+	raw := goDiffFixture("main.go")
+	out := styleDiff(raw, diffStyleOptions{highlight: true, wordDiff: true})
+	if got := ansiSGR.ReplaceAllString(out, ""); got != raw {
+		t.Fatalf("styled text without escapes = %q, want %q", got, raw)
+	}
+}
+
+func TestStyleDiffHighlightsByExtension(t *testing.T) {
+	plain := styleDiffContent(goDiffFixture("main.go"))
+	if styleDiff(goDiffFixture("main.go"), diffStyleOptions{highlight: true}) == plain {
+		t.Fatal("a .go file should be highlighted")
+	}
+	unknown := goDiffFixture("notes.zzz-unknown")
+	if styleDiff(unknown, diffStyleOptions{highlight: true}) != styleDiffContent(unknown) {
+		t.Fatal("a file without a known language should keep the plain colours")
+	}
+}
+
+func TestStyleDiffWordDiffEmphasisesChangedWord(t *testing.T) {
+	raw := goDiffFixture("notes.zzz-unknown")
+	lines := strings.Split(styleDiff(raw, diffStyleOptions{wordDiff: true}), "\n")
+	added := lines[7]
+	if !regexp.MustCompile("\x1b\\[[0-9;]*;48;[0-9;]*mhi\x1b").MatchString(added) {
+		t.Fatalf("added line should carry the emphasis background: %q", added)
+	}
+	if plain := styleDiffContent(raw); strings.Split(plain, "\n")[7] == added {
+		t.Fatal("word diff should change the added line's styling")
+	}
+}
+
+func TestStyleDiffCountsHunkBody(t *testing.T) {
+	raw := strings.Join([]string{
+		"--- a/q.sql",
+		"+++ b/q.sql",
+		"@@ -1,2 +1 @@",
+		"--- old comment",
+		" select 1;",
+		"diff --git a/next b/next",
+	}, "\n")
+	lines := strings.Split(styleDiffContent(raw), "\n")
+	if lines[3] != diffStyleDeleted.Render("--- old comment") {
+		t.Fatalf("deleted \"--- \" line inside a hunk = %q, want deleted style", lines[3])
+	}
+	if lines[5] != diffStyleHeader.Render("diff --git a/next b/next") {
+		t.Fatalf("line after the hunk = %q, want header style", lines[5])
+	}
+}
+
+func TestHunkLineCounts(t *testing.T) {
+	tests := []struct {
+		line       string
+		oldN, newN int
+		ok         bool
+	}{
+		{"@@ -1,3 +1,4 @@ func main()", 3, 4, true},
+		{"@@ -5 +5 @@", 1, 1, true},
+		{"@@ -0,0 +1,2 @@", 0, 2, true},
+		{"@@@ -1,2 -1,2 +1,3 @@@", 0, 0, false},
+		{"@@ bogus", 0, 0, false},
+	}
+	for _, tt := range tests {
+		oldN, newN, ok := hunkLineCounts(tt.line)
+		if oldN != tt.oldN || newN != tt.newN || ok != tt.ok {
+			t.Errorf("hunkLineCounts(%q) = %d, %d, %v; want %d, %d, %v",
+				tt.line, oldN, newN, ok, tt.oldN, tt.newN, tt.ok)
+		}
+	}
+}
+
+func TestDiffStyleOptionsFollowConfigAndNoColor(t *testing.T) {
+	m := newTestModel()
+	if got := m.diffStyleOptions(); got != (diffStyleOptions{highlight: true, wordDiff: true}) {
+		t.Fatalf("default options = %+v, want both on", got)
+	}
+	off := false
+	m.config = &scanner.Config{}
+	m.config.Diff.Highlight = &off
+	if got := m.diffStyleOptions(); got != (diffStyleOptions{wordDiff: true}) {
+		t.Fatalf("diff.highlight false = %+v, want only word diff", got)
+	}
+
+	lipgloss.SetColorProfile(termenv.Ascii)
+	defer lipgloss.SetColorProfile(termenv.ANSI256)
+	if got := m.diffStyleOptions(); got != (diffStyleOptions{}) {
+		t.Fatalf("options without colour = %+v, want both off", got)
+	}
+	raw := goDiffFixture("main.go")
+	if got := styleDiff(raw, m.diffStyleOptions()); got != raw {
+		t.Fatalf("diff without colour = %q, want the raw text", got)
+	}
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"log"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// styleFilterMatch highlights the characters a filter query matched.
+var styleFilterMatch = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true).Underline(true)
+
+// fuzzyMatch reports whether every rune of query appears in s in order
+// (case-insensitively) and returns the rune indexes of s it matched.
+func fuzzyMatch(query, s string) ([]int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return nil, true
+	}
+	var idx []int
+	i := 0
+	for j, r := range []rune(s) {
+		if unicode.ToLower(r) == q[i] {
+			idx = append(idx, j)
+			i++
+			if i == len(q) {
+				return idx, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// filterablePane reports whether / filters pane p.
+func filterablePane(p pane) bool {
+	return p == paneRepo || p == paneStatus || p == paneBranches
+}
+
+// filterQuery returns the active filter for pane p ("" when unfiltered).
+func (m *model) filterQuery(p pane) string {
+	return m.filters[p]
+}
+
+// repoFilterHit records how a repository matched the repository filter: the
+// matched rune indexes of its path, or else the branch or file that matched.
+type repoFilterHit struct {
+	pathIdx []int
+	via     string
+}
+
+// markRepoFilterHits records which repositories' path, local branch names, or
+// changed file paths fuzzy-match the repository filter, for highlighting and
+// n/N. Unlike Status and Branches, the repository list keeps the rows that do
+// not match, dimmed, so n/N have matches to jump between.
+func (m *model) markRepoFilterHits(paths []string) {
+	q := m.filterQuery(paneRepo)
+	m.repoFilterHits = nil
+	if q == "" {
+		return
+	}
+	m.repoFilterHits = make(map[string]repoFilterHit)
+	for _, p := range paths {
+		if hit, ok := m.matchRepo(q, p); ok {
+			m.repoFilterHits[p] = hit
+		}
+	}
+}
+
+// repoFilterMiss reports whether the repository filter is active and path is
+// a repository it does not match.
+func (m *model) repoFilterMiss(path string) bool {
+	if m.filterQuery(paneRepo) == "" || isGroupRow(path) {
+		return false
+	}
+	_, ok := m.repoFilterHits[path]
+	return !ok
+}
+
+// matchRepo matches q against path, then its branches, then its changed files.
+func (m *model) matchRepo(q, path string) (repoFilterHit, bool) {
+	if idx, ok := fuzzyMatch(q, path); ok {
+		return repoFilterHit{pathIdx: idx}, true
+	}
+	st, ok := m.repositories.Get(path)
+	if !ok {
+		return repoFilterHit{}, false
+	}
+	for _, lb := range st.Branches {
+		if _, ok := fuzzyMatch(q, lb.Name); ok {
+			return repoFilterHit{via: "branch " + lb.Name}, true
+		}
+	}
+	for _, e := range st.Porcelain.Entries {
+		if _, ok := fuzzyMatch(q, e.Path); ok {
+			return repoFilterHit{via: "file " + e.Path}, true
+		}
+	}
+	return repoFilterHit{}, false
+}
+
+// highlightRunes styles the runes of s at idx (offset by off) with styleFilterMatch.
+func highlightRunes(s string, idx []int, off int) string {
+	if len(idx) == 0 {
+		return s
+	}
+	marks := make(map[int]bool, len(idx))
+	for _, i := range idx {
+		marks[i+off] = true
+	}
+	var b strings.Builder
+	for i, r := range []rune(s) {
+		if marks[i] {
+			b.WriteString(styleFilterMatch.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// statusEntryMatches reports whether the Status filter keeps entry.
+func (m *model) statusEntryMatches(entry scanner.PorcelainEntry) bool {
+	q := m.filterQuery(paneStatus)
+	if q == "" {
+		return true
+	}
+	_, ok := fuzzyMatch(q, entry.Path)
+	if !ok && entry.OriginalPath != "" {
+		_, ok = fuzzyMatch(q, entry.OriginalPath)
+	}
+	return ok
+}
+
+// branchMatches reports whether the Branches filter keeps lb.
+func (m *model) branchMatches(lb scanner.LocalBranchRef) bool {
+	_, ok := fuzzyMatch(m.filterQuery(paneBranches), lb.Name)
+	return ok
+}
+
+// withFilterTitle appends the pane's filter (with a cursor while typing) to title.
+func (m *model) withFilterTitle(p pane, title string) string {
+	q := m.filterQuery(p)
+	editing := m.filterEditing && m.filterPane == p
+	if q == "" && !editing {
+		return title
+	}
+	title += "  /" + q
+	if editing {
+		title += "▏"
+	}
+	return title
+}
+
+// openFilter starts typing a filter for the focused pane, keeping any query.
+func (m *model) openFilter() bool {
+	if m.err != nil || !filterablePane(m.focus) {
+		return false
+	}
+	m.filterEditing = true
+	m.filterPane = m.focus
+	return true
+}
+
+// setFilter replaces pane p's query and refreshes the affected list.
+func (m *model) setFilter(p pane, q string) {
+	if m.filters == nil {
+		m.filters = make(map[pane]string)
+	}
+	if q == "" {
+		delete(m.filters, p)
+	} else {
+		m.filters[p] = q
+	}
+	if p == paneRepo {
+		m.rebuildRepoList()
+		if m.cursor < len(m.repoList) && (isGroupRow(m.repoList[m.cursor]) || m.repoFilterMiss(m.repoList[m.cursor])) {
+			m.jumpRepoFilterMatch(false)
+		}
+		m.diffNeedsRefresh = true
+	}
+	m.syncViewports()
+}
+
+// handleFilterKey edits the filter query while typing: Enter keeps it, Esc
+// clears it, and every change re-filters the pane immediately.
+func (m *model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.filterPane
+	q := m.filterQuery(p)
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		m.filterEditing = false
+		if q != "" {
+			log.Printf("filter %q: %d match(es)", q, m.filterMatchCount(p))
+		}
+		return m, nil
+	case tea.KeyEsc:
+		m.filterEditing = false
+		m.setFilter(p, "")
+		return m, nil
+	case tea.KeyBackspace:
+		if r := []rune(q); len(r) > 0 {
+			m.setFilter(p, string(r[:len(r)-1]))
+		}
+		return m, nil
+	case tea.KeyCtrlU:
+		m.setFilter(p, "")
+		return m, nil
+	case tea.KeyRunes, tea.KeySpace:
+		m.setFilter(p, q+string(msg.Runes))
+	}
+	return m, nil
+}
+
+// filterMatchCount counts the rows pane p's filter matches.
+func (m *model) filterMatchCount(p pane) int {
+	switch p {
+	case paneRepo:
+		return len(m.repoFilterHits)
+	case paneStatus:
+		return len(m.statusPaths)
+	}
+	return len(m.branchTable.Rows())
+}
+
+// jumpFilterMatch moves the Repositories selection to the next (or previous)
+// matching repository, wrapping around. It reports false when Repositories is
+// not focused or is unfiltered: Status and Branches drop the rows that do not
+// match, so there is nothing to jump over.
+func (m *model) jumpFilterMatch(back bool) bool {
+	if m.focus != paneRepo || m.filterQuery(paneRepo) == "" {
+		return false
+	}
+	m.jumpRepoFilterMatch(back)
+	m.syncViewports()
+	return true
+}
+
+// jumpRepoFilterMatch moves the repository cursor to the next (or previous)
+// row the filter matches, wrapping around; it stays put when nothing matches.
+func (m *model) jumpRepoFilterMatch(back bool) {
+	step := 1
+	if back {
+		step = -1
+	}
+	n := len(m.repoList)
+	for i := 1; i <= n; i++ {
+		c := ((m.cursor+step*i)%n + n) % n
+		if _, ok := m.repoFilterHits[m.repoList[c]]; ok {
+			m.cursor = c
+			m.statusFileSelected = false
+			m.diffNeedsRefresh = true
+			return
+		}
+	}
+}
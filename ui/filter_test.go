@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	for _, tt := range []struct {
+		q, s string
+		idx  []int
+		ok   bool
+	}{
+		{"", "anything", nil, true},
+		{"dgt", "dirtygit", []int{0, 5, 7}, true},
+		{"DG", "dirtygit", []int{0, 5}, true},
+		{"gd", "dirtygit", nil, false},
+	} {
+		idx, ok := fuzzyMatch(tt.q, tt.s)
+		if ok != tt.ok || len(idx) != len(tt.idx) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v", tt.q, tt.s, idx, ok)
+			continue
+		}
+		for i := range idx {
+			if idx[i] != tt.idx[i] {
+				t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.q, tt.s, idx, tt.idx)
+			}
+		}
+	}
+}
+
+func typeKeys(m *model, s string) {
+	for _, r := range s {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestRepoFilterMatchesPathsBranchesAndFiles(t *testing.T) {
+	m := newTestModel()
+	m.width = 100
+	m.height = 30
+	m.repositories.AddResult("/src/api", scanner.RepoStatus{Branch: "main"})
+	m.repositories.AddResult("/src/web", scanner.RepoStatus{
+		Branch:   "main",
+		Branches: []scanner.LocalBranchRef{{Name: "apix-fix"}},
+	})
+	m.repositories.AddResult("/src/docs", scanner.RepoStatus{
+		Porcelain: scanner.PorcelainStatus{Entries: []scanner.PorcelainEntry{{Path: "apix.md"}}},
+	})
+	m.repositories.AddResult("/src/other", scanner.RepoStatus{Branch: "main"})
+	m.repoList = m.visibleRepoPaths()
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !m.filterEditing {
+		t.Fatal("/ should start editing the filter")
+	}
+	typeKeys(m, "apix")
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filterEditing || len(m.repoList) != 4 || m.filterMatchCount(paneRepo) != 2 {
+		t.Fatalf("editing=%v list=%v matches=%d", m.filterEditing, m.repoList, m.filterMatchCount(paneRepo))
+	}
+	if m.currentRepo() != "/src/docs" {
+		t.Fatalf("the filter should select the first match, got %q", m.currentRepo())
+	}
+	if got := m.repoListRow("/src/web", 80, nil, true); !strings.Contains(got, "(branch apix-fix)") {
+		t.Fatalf("row = %q", got)
+	}
+	if got := m.repoListRow("/src/other", 80, nil, true); got != styleDim.Render("/src/other") {
+		t.Fatalf("unmatched row should be dimmed: %q", got)
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.currentRepo() != "/src/web" {
+		t.Fatalf("n should skip the unmatched row, got %q", m.currentRepo())
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.currentRepo() != "/src/docs" {
+		t.Fatalf("n should wrap to the first match, got %q", m.currentRepo())
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	if m.currentRepo() != "/src/web" {
+		t.Fatalf("N should wrap to the last match, got %q", m.currentRepo())
+	}
+	if !strings.Contains(m.repoPaneTitle(), "/apix") {
+		t.Fatalf("title = %q", m.repoPaneTitle())
+	}
+
+	m.setFilter(paneRepo, "sapi")
+	if m.filterMatchCount(paneRepo) != 1 || m.currentRepo() != "/src/api" {
+		t.Fatalf("path match: %v selected=%q", m.repoFilterHits, m.currentRepo())
+	}
+	if got := m.repoListRow("/src/api", 80, nil, true); !strings.Contains(got, styleFilterMatch.Render("s")) {
+		t.Fatalf("row should highlight matches: %q", got)
+	}
+
+	m.setFilter(paneRepo, "src")
+	m.cursor = 3
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filterQuery(paneRepo) != "" || len(m.repoList) != 4 || m.currentRepo() != "/src/web" {
+		t.Fatalf("Esc should clear the filter: list=%v selected=%q", m.repoList, m.currentRepo())
+	}
+}
+
+func TestStatusAndBranchFilters(t *testing.T) {
+	m := newTestModel()
+	m.width = 100
+	m.height = 30
+	m.repositories.AddResult("/r", scanner.RepoStatus{
+		Porcelain: scanner.PorcelainStatus{Entries: []scanner.PorcelainEntry{
+			{Staging: ' ', Worktree: 'M', Path: "cmd/main.go"},
+			{Staging: ' ', Worktree: 'M', Path: "README.md"},
+		}},
+		FilteredBranches: []scanner.LocalBranchRef{{Name: "feature"}, {Name: "main"}},
+	})
+	m.repoList = m.visibleRepoPaths()
+
+	m.focus = paneStatus
+	m.setFilter(paneStatus, "main")
+	if len(m.statusPaths) != 1 || m.statusPaths[0] != "cmd/main.go" {
+		t.Fatalf("status paths = %v", m.statusPaths)
+	}
+	if m.jumpFilterMatch(false) {
+		t.Fatal("n should not jump in Status, which only lists matches")
+	}
+
+	m.focus = paneBranches
+	m.setFilter(paneBranches, "feat")
+	if rows := m.branchTable.Rows(); len(rows) != 1 || rows[0][0] != "feature" {
+		t.Fatalf("branch rows = %v", rows)
+	}
+}
@@ -28,21 +28,36 @@ func (m *model) openCurrentRepo() {
 	}
 }
 
-func gitAdd(repo, path string) error {
+// runGitInRepo runs git with args in repo. On failure the trimmed combined
+// output is appended to the error so the Log pane shows git's message.
+func runGitInRepo(repo string, args ...string) (string, error) {
+	return runGitInRepoWithInput(repo, "", args...)
+}
+
+// runGitInRepoWithInput is runGitInRepo with input fed to git's stdin.
+func runGitInRepoWithInput(repo, input string, args ...string) (string, error) {
 	if repo == "" {
-		return fmt.Errorf("no repository selected")
+		return "", fmt.Errorf("no repository selected")
 	}
-	cmd := exec.Command("git", "add", "--", path)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = repo
+	if input != "" {
+		cmd.Stdin = strings.NewReader(input)
+	}
 	out, err := cmd.CombinedOutput()
+	s := strings.TrimSpace(string(out))
 	if err != nil {
-		s := strings.TrimSpace(string(out))
 		if s != "" {
-			return fmt.Errorf("%w: %s", err, s)
+			return s, fmt.Errorf("%w: %s", err, s)
 		}
-		return err
+		return s, err
 	}
-	return nil
+	return s, nil
+}
+
+func gitAdd(repo string, paths ...string) error {
+	_, err := runGitInRepo(repo, append([]string{"add", "--"}, paths...)...)
+	return err
 }
 
 // statusPathUnderRepo resolves a git status path (slash-separated, relative to the repository
@@ -81,45 +96,42 @@ func removeStatusPathOnDisk(repo, gitRelPath string) error {
 	return os.RemoveAll(abs)
 }
 
-func gitResetPath(repo, path string) error {
-	if repo == "" {
-		return fmt.Errorf("no repository selected")
-	}
-	cmd := exec.Command("git", "reset", "HEAD", "--", path)
-	cmd.Dir = repo
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		s := strings.TrimSpace(string(out))
-		if s != "" {
-			return fmt.Errorf("%w: %s", err, s)
-		}
-		return err
-	}
-	return nil
+func gitResetPath(repo string, paths ...string) error {
+	_, err := runGitInRepo(repo, append([]string{"reset", "HEAD", "--"}, paths...)...)
+	return err
 }
 
-// gitCheckoutHeadPath restores the path's index and working tree content from HEAD
-// (same as `git checkout HEAD -- <path>`).
-func gitCheckoutHeadPath(repo, path string) error {
-	if repo == "" {
-		return fmt.Errorf("no repository selected")
-	}
-	cmd := exec.Command("git", "checkout", "HEAD", "--", path)
-	cmd.Dir = repo
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		s := strings.TrimSpace(string(out))
-		if s != "" {
-			return fmt.Errorf("%w: %s", err, s)
-		}
-		return err
+// gitCheckoutHeadPath restores the paths' index and working tree content from HEAD
+// (same as `git checkout HEAD -- <path>...`).
+func gitCheckoutHeadPath(repo string, paths ...string) error {
+	_, err := runGitInRepo(repo, append([]string{"checkout", "HEAD", "--"}, paths...)...)
+	return err
+}
+
+// gitApplyPatch runs git apply with args (e.g. --cached, -R), reading patch from stdin.
+func gitApplyPatch(repo, patch string, args ...string) error {
+	_, err := runGitInRepoWithInput(repo, patch, append(append([]string{"apply"}, args...), "-")...)
+	return err
+}
+
+// gitCreateBranchAt creates a new branch pointing at commit (`git branch <name> <commit>`).
+func gitCreateBranchAt(repo, name, commit string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("branch name is empty")
 	}
-	return nil
+	_, err := runGitInRepo(repo, "branch", "--", name, commit)
+	return err
 }
 
 // refreshRepoStatusAfterGit re-runs status for the current repo so the UI matches git.
 func (m *model) refreshRepoStatusAfterGit() {
-	repo := m.currentRepo()
+	m.refreshRepoStatus(m.currentRepo())
+}
+
+// refreshRepoStatus re-runs status for repo, dropping it from the list when it
+// is no longer dirty (or only behind, unless that category is shown). The
+// selected repository stays selected when it remains.
+func (m *model) refreshRepoStatus(repo string) {
 	if repo == "" || m.config == nil {
 		return
 	}
@@ -128,13 +140,10 @@ func (m *model) refreshRepoStatusAfterGit() {
 		log.Printf("refresh repo status: %v", err)
 		return
 	}
-	if include {
+	if include || rs.BehindOnly {
 		m.repositories.AddResult(repo, rs)
 	} else {
 		m.repositories.Delete(repo)
-		m.repoList = m.repositories.SortedRepoPaths()
-		if m.cursor >= len(m.repoList) {
-			m.cursor = max(0, len(m.repoList)-1)
-		}
 	}
+	m.rebuildRepoList()
 }
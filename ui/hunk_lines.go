@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseHunkHeader reads "@@ -a,b +c,d @@ rest"; a missing count means 1.
+func parseHunkHeader(line string) (oldStart, oldCount, newStart, newCount int, rest string, ok bool) {
+	s, found := strings.CutPrefix(line, "@@ -")
+	if !found {
+		return 0, 0, 0, 0, "", false
+	}
+	ranges, rest, found := strings.Cut(s, " @@")
+	if !found {
+		return 0, 0, 0, 0, "", false
+	}
+	oldRange, newRange, found := strings.Cut(ranges, " +")
+	if !found {
+		return 0, 0, 0, 0, "", false
+	}
+	parse := func(r string) (start, count int, ok bool) {
+		a, b, hasCount := strings.Cut(r, ",")
+		start, err := strconv.Atoi(a)
+		if err != nil {
+			return 0, 0, false
+		}
+		count = 1
+		if hasCount {
+			if count, err = strconv.Atoi(b); err != nil {
+				return 0, 0, false
+			}
+		}
+		return start, count, true
+	}
+	oldStart, oldCount, ok1 := parse(oldRange)
+	newStart, newCount, ok2 := parse(newRange)
+	return oldStart, oldCount, newStart, newCount, rest, ok1 && ok2
+}
+
+// pairedStart is the start line for the side of a single-hunk patch that is not
+// anchored, following diff's convention for empty ranges (the start names the line
+// before an insertion).
+func pairedStart(anchorStart, anchorCount, count int) int {
+	switch {
+	case anchorCount == 0:
+		return anchorStart + 1
+	case count == 0:
+		return anchorStart - 1
+	}
+	return anchorStart
+}
+
+// withLines narrows the hunk to the +/- lines with indexes lo..hi in h.lines and
+// recomputes the header, returning false when that range has no changed lines.
+//
+// The forward patch (reverse false) applies on top of the old side: unselected
+// "-" lines become context and unselected "+" lines are dropped, so applying it
+// to the index stages just the selection. The reverse patch is meant for
+// git apply -R against the new side: unselected "+" lines become context and
+// unselected "-" lines are dropped, so the selection alone is undone (unstaging
+// from the index, or discarding from the worktree).
+func (h diffHunk) withLines(lo, hi int, reverse bool) (diffHunk, bool) {
+	oldStart, _, newStart, _, rest, ok := parseHunkHeader(h.lines[0])
+	if !ok {
+		return diffHunk{}, false
+	}
+	var (
+		body         []string
+		oldN, newN   int
+		changed      bool
+		keptPrevious = true
+	)
+	for i := 1; i < len(h.lines); i++ {
+		line := h.lines[i]
+		selected := i >= lo && i <= hi
+		switch op := line[0]; {
+		case op == '\\':
+			// "\ No newline at end of file" belongs to the line before it.
+			if keptPrevious {
+				body = append(body, line)
+			}
+			continue
+		case op == ' ':
+			body = append(body, line)
+			oldN++
+			newN++
+		case selected && op == '-':
+			body = append(body, line)
+			oldN++
+			changed = true
+		case selected && op == '+':
+			body = append(body, line)
+			newN++
+			changed = true
+		case op == '-' && !reverse, op == '+' && reverse:
+			body = append(body, " "+line[1:])
+			oldN++
+			newN++
+		default:
+			keptPrevious = false
+			continue
+		}
+		keptPrevious = true
+	}
+	if !changed {
+		return diffHunk{}, false
+	}
+	if reverse {
+		oldStart = pairedStart(newStart, newN, oldN)
+	} else {
+		newStart = pairedStart(oldStart, oldN, newN)
+	}
+	header := fmt.Sprintf("@@ -%d,%d +%d,%d @@%s", oldStart, oldN, newStart, newN, rest)
+	return diffHunk{
+		path:   h.path,
+		header: h.header,
+		lines:  append([]string{header}, body...),
+		start:  h.start,
+	}, true
+}
+
+// selectedLineRange returns the hunk line indexes covered by the line selection.
+func (m *model) selectedLineRange() (lo, hi int) {
+	return min(m.diffLineAnchor, m.diffLineCursor), max(m.diffLineAnchor, m.diffLineCursor)
+}
+
+// toggleLineMode starts (or ends) a line selection inside the selected hunk,
+// anchored on its first changed line.
+func (m *model) toggleLineMode() bool {
+	h, ok := m.selectedHunk()
+	if !ok || m.focus != paneDiff {
+		return false
+	}
+	if m.diffLineMode {
+		m.diffLineMode = false
+	} else {
+		m.diffLineMode = true
+		m.diffLineCursor = 1
+		for i, line := range h.lines[1:] {
+			if line[0] == '+' || line[0] == '-' {
+				m.diffLineCursor = i + 1
+				break
+			}
+		}
+		m.diffLineAnchor = m.diffLineCursor
+	}
+	m.showSelectedHunk()
+	return true
+}
+
+// moveLineCursor extends the line selection up or down within the hunk and keeps
+// the cursor line on screen.
+func (m *model) moveLineCursor(up bool) {
+	h, ok := m.selectedHunk()
+	if !ok {
+		return
+	}
+	if up {
+		m.diffLineCursor = max(1, m.diffLineCursor-1)
+	} else {
+		m.diffLineCursor = min(len(h.lines)-1, m.diffLineCursor+1)
+	}
+	m.renderDiffContent()
+	m.diffVP.SetContent(m.diffContent)
+	row := h.start + m.diffLineCursor
+	if row < m.diffVP.YOffset {
+		m.diffVP.SetYOffset(row)
+	} else if row >= m.diffVP.YOffset+m.diffVP.Height {
+		m.diffVP.SetYOffset(row - m.diffVP.Height + 1)
+	}
+}
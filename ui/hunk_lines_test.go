@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+const (
+	lineFixtureBase = "func main() {\n\tx := 1\n\treturn\n}\n"
+	lineFixtureWork = "func main() {\n\tx := 2\n\tfmt.Println(\"debug\")\n\treturn\n}\n"
+)
+
+func TestHunkWithLines(t *testing.T) {
+	h := parseDiffHunks("diff --git a/m.go b/m.go\n--- a/m.go\n+++ b/m.go\n" +
+		"@@ -4,4 +4,5 @@ package main\n func main() {\n-\tx := 1\n+\tx := 2\n+\tfmt.Println(\"debug\")\n \treturn\n }\n")[0]
+
+	fwd, ok := h.withLines(2, 3, false)
+	if !ok {
+		t.Fatal("forward selection should have changes")
+	}
+	want := []string{"@@ -4,4 +4,4 @@ package main", " func main() {", "-\tx := 1", "+\tx := 2", " \treturn", " }"}
+	if strings.Join(fwd.lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("forward lines = %q", fwd.lines)
+	}
+
+	rev, _ := h.withLines(4, 4, true)
+	want = []string{"@@ -4,4 +4,5 @@ package main", " func main() {", " \tx := 2", "+\tfmt.Println(\"debug\")", " \treturn", " }"}
+	if strings.Join(rev.lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("reverse lines = %q", rev.lines)
+	}
+
+	if _, ok := h.withLines(1, 1, false); ok {
+		t.Fatal("a context-only selection has nothing to apply")
+	}
+}
+
+func TestLineRangePatchesApply(t *testing.T) {
+	cases := []struct {
+		name       string
+		base, work string
+		// staged stages the whole change first and selects from the Staged diff.
+		staged bool
+		lo, hi int
+		op     string
+		// index and worktree are the expected contents afterwards.
+		index, worktree string
+	}{
+		{
+			name: "stage the fix but not the debug line",
+			base: lineFixtureBase, work: lineFixtureWork,
+			lo: 2, hi: 3, op: "stage",
+			index:    "func main() {\n\tx := 2\n\treturn\n}\n",
+			worktree: lineFixtureWork,
+		},
+		{
+			name: "stage only an added line",
+			base: lineFixtureBase, work: lineFixtureWork,
+			lo: 4, hi: 4, op: "stage",
+			index:    "func main() {\n\tx := 1\n\tfmt.Println(\"debug\")\n\treturn\n}\n",
+			worktree: lineFixtureWork,
+		},
+		{
+			name: "stage only a removed line",
+			base: lineFixtureBase, work: lineFixtureWork,
+			lo: 2, hi: 2, op: "stage",
+			index:    "func main() {\n\treturn\n}\n",
+			worktree: lineFixtureWork,
+		},
+		{
+			name: "stage the first line of a file that was empty",
+			base: "", work: "one\ntwo\n",
+			lo: 1, hi: 1, op: "stage",
+			index:    "one\n",
+			worktree: "one\ntwo\n",
+		},
+		{
+			name: "unstage the debug line",
+			base: lineFixtureBase, work: lineFixtureWork, staged: true,
+			lo: 4, hi: 4, op: "unstage",
+			index:    "func main() {\n\tx := 2\n\treturn\n}\n",
+			worktree: lineFixtureWork,
+		},
+		{
+			name: "unstage a removed line",
+			base: lineFixtureBase, work: lineFixtureWork, staged: true,
+			lo: 2, hi: 2, op: "unstage",
+			index:    "func main() {\n\tx := 1\n\tx := 2\n\tfmt.Println(\"debug\")\n\treturn\n}\n",
+			worktree: lineFixtureWork,
+		},
+		{
+			name: "discard the debug line",
+			base: lineFixtureBase, work: lineFixtureWork,
+			lo: 4, hi: 4, op: "discard",
+			index:    lineFixtureBase,
+			worktree: "func main() {\n\tx := 2\n\treturn\n}\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := lineFixtureRepo(t, tc.base, tc.work)
+			if tc.staged {
+				runGitT(t, repo, "add", "m.go")
+			}
+			raw, err := gitDiff(repo, "m.go", tc.staged)
+			if err != nil {
+				t.Fatalf("git diff: %v", err)
+			}
+			hunks := parseDiffHunks(raw)
+			if len(hunks) != 1 {
+				t.Fatalf("got %d hunks:\n%s", len(hunks), raw)
+			}
+			h, ok := hunks[0].withLines(tc.lo, tc.hi, tc.op != "stage")
+			if !ok {
+				t.Fatal("selection has no changes")
+			}
+			args := map[string][]string{
+				"stage":   {"--cached"},
+				"unstage": {"--cached", "-R"},
+				"discard": {"-R"},
+			}[tc.op]
+			if err := gitApplyPatch(repo, h.patch(), args...); err != nil {
+				t.Fatalf("git apply %v: %v\n%s", args, err, h.patch())
+			}
+			if got := runGitT(t, repo, "show", ":m.go"); got != tc.index {
+				t.Errorf("index =\n%q\nwant\n%q", got, tc.index)
+			}
+			if got, _ := os.ReadFile(filepath.Join(repo, "m.go")); string(got) != tc.worktree {
+				t.Errorf("worktree =\n%q\nwant\n%q", got, tc.worktree)
+			}
+		})
+	}
+}
+
+func TestGitDiffIgnoresUserDiffFormat(t *testing.T) {
+	repo := lineFixtureRepo(t, lineFixtureBase, lineFixtureWork)
+	runGitT(t, repo, "config", "color.diff", "always")
+	runGitT(t, repo, "config", "diff.noprefix", "true")
+	runGitT(t, repo, "config", "diff.external", "false")
+	raw, err := gitDiff(repo, "m.go", false)
+	if err != nil {
+		t.Fatalf("git diff: %v", err)
+	}
+	if strings.Contains(raw, "\x1b[") || !strings.Contains(raw, "--- a/m.go\n+++ b/m.go\n") {
+		t.Fatalf("diff should be plain with a/ and b/ prefixes:\n%q", raw)
+	}
+	hunks := parseDiffHunks(raw)
+	if len(hunks) != 1 {
+		t.Fatalf("got %d hunks:\n%s", len(hunks), raw)
+	}
+	if err := gitApplyPatch(repo, hunks[0].patch(), "--cached"); err != nil {
+		t.Fatalf("git apply: %v\n%s", err, hunks[0].patch())
+	}
+	if got := runGitT(t, repo, "show", ":m.go"); got != lineFixtureWork {
+		t.Fatalf("index = %q, want the worktree", got)
+	}
+}
+
+func TestDiffLineSelectionStagesLines(t *testing.T) {
+	repo := lineFixtureRepo(t, lineFixtureBase, lineFixtureWork)
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.focus = paneDiff
+	m.syncViewports()
+
+	for _, k := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("]")},
+		{Type: tea.KeyRunes, Runes: []rune("v")},
+		{Type: tea.KeyDown},
+	} {
+		m.handleKey(k)
+	}
+	if !m.diffLineMode || !strings.Contains(m.diffPaneBorderTitle(), "lines 2-3") {
+		t.Fatalf("line mode = %v, title %q", m.diffLineMode, m.diffPaneBorderTitle())
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if got := runGitT(t, repo, "show", ":m.go"); got != "func main() {\n\tx := 2\n\treturn\n}\n" {
+		t.Fatalf("index = %q", got)
+	}
+	if m.diffLineMode {
+		t.Fatal("line selection should end once the diff reloads")
+	}
+}
+
+// lineFixtureRepo commits base as m.go and leaves work in the worktree.
+func lineFixtureRepo(t *testing.T, base, work string) string {
+	t.Helper()
+	repo := filepath.Join(t.TempDir(), "r")
+	runGitT(t, filepath.Dir(repo), "init", "-q", repo)
+	file := filepath.Join(repo, "m.go")
+	if err := os.WriteFile(file, []byte(base), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitT(t, repo, "add", "m.go")
+	runGitT(t, repo, "commit", "-qm", "base")
+	if err := os.WriteFile(file, []byte(work), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+// runGitT runs git in repo, committing as a fixed test author, and returns its
+// combined output.
+func runGitT(t *testing.T, repo string, arg ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", repo}, arg...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=u", "GIT_AUTHOR_EMAIL=u@x", "GIT_COMMITTER_NAME=u", "GIT_COMMITTER_EMAIL=u@x")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", arg, err, out)
+	}
+	return string(out)
+}
+
+// cloneWithBareOrigin seeds a repository with an empty commit on main, clones
+// it bare as origin.git and returns a clone of that along with origin.git's
+// path. All three sit side by side in one temporary directory.
+func cloneWithBareOrigin(t *testing.T) (repo, origin string) {
+	t.Helper()
+	root := t.TempDir()
+	seed := filepath.Join(root, "seed")
+	runGitT(t, root, "init", "-q", "-b", "main", seed)
+	runGitT(t, seed, "commit", "-q", "--allow-empty", "-m", "base")
+	origin = filepath.Join(root, "origin.git")
+	runGitT(t, root, "clone", "-q", "--bare", seed, origin)
+	repo = filepath.Join(root, "clone")
+	runGitT(t, root, "clone", "-q", origin, repo)
+	return repo, origin
+}
@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// discardHunkPreviewRows caps how many hunk lines the discard confirmation shows.
+const discardHunkPreviewRows = 8
+
+// diffHunk is one @@ section of git diff output together with the header of the
+// file it belongs to, so it can be applied on its own.
+type diffHunk struct {
+	// path is the file the hunk changes (new path for renames).
+	path string
+	// header holds the file's lines from "diff --git" up to the first "@@".
+	header []string
+	// lines holds the "@@" line and the hunk body.
+	lines []string
+	// start is the index of the "@@" line in the diff output.
+	start int
+}
+
+// rangeLabel is the "-a,b +c,d" part of the hunk's "@@" line.
+func (h diffHunk) rangeLabel() string {
+	s := strings.TrimPrefix(h.lines[0], "@@ ")
+	if i := strings.Index(s, " @@"); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// patch is a single-hunk patch suitable for git apply.
+func (h diffHunk) patch() string {
+	return strings.Join(h.header, "\n") + "\n" + strings.Join(h.lines, "\n") + "\n"
+}
+
+// parseDiffHunks splits git diff output into hunks. Files without hunks (binary
+// or mode-only changes) contribute none.
+func parseDiffHunks(raw string) []diffHunk {
+	var (
+		hunks  []diffHunk
+		header []string
+		path   string
+		cur    *diffHunk
+	)
+	flush := func() {
+		if cur != nil {
+			hunks = append(hunks, *cur)
+			cur = nil
+		}
+	}
+	for i, line := range strings.Split(raw, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			header = []string{line}
+			path = ""
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			if header == nil {
+				continue
+			}
+			cur = &diffHunk{path: path, header: header, lines: []string{line}, start: i}
+		case cur != nil:
+			// Body lines always carry a prefix; an empty line is the output's trailing newline.
+			if line != "" {
+				cur.lines = append(cur.lines, line)
+			}
+		case header != nil:
+			header = append(header, line)
+			if p, ok := strings.CutPrefix(line, "+++ "); ok && p != "/dev/null" {
+				path = strings.TrimPrefix(p, "b/")
+			} else if p, ok := strings.CutPrefix(line, "--- "); ok && path == "" && p != "/dev/null" {
+				path = strings.TrimPrefix(p, "a/")
+			}
+		}
+	}
+	flush()
+	return hunks
+}
+
+// selectedHunk returns the hunk under the Diff pane's hunk cursor.
+func (m *model) selectedHunk() (diffHunk, bool) {
+	if !m.diffHunkSelected || m.diffHunkCursor >= len(m.diffHunks) {
+		return diffHunk{}, false
+	}
+	return m.diffHunks[m.diffHunkCursor], true
+}
+
+// selectedPatchHunk is what a/r/C apply: the selected hunk, or with a line
+// selection the hunk narrowed to those lines (see withLines for reverse).
+func (m *model) selectedPatchHunk(reverse bool) (diffHunk, bool) {
+	h, ok := m.selectedHunk()
+	if !ok || !m.diffLineMode {
+		return h, ok
+	}
+	lo, hi := m.selectedLineRange()
+	return h.withLines(lo, hi, reverse)
+}
+
+// selectionNoun names what a/r/C act on, for log lines and the confirmation.
+func (m *model) selectionNoun() string {
+	if m.diffLineMode {
+		return "selected lines"
+	}
+	return "hunk"
+}
+
+// hunkOpsReady is true when a/r/C should act on the selected hunk rather than the file.
+func (m *model) hunkOpsReady() bool {
+	_, ok := m.selectedHunk()
+	return ok && m.focus == paneDiff
+}
+
+// moveHunkCursor selects the next (or previous) hunk in the Diff pane. The first
+// press picks the first hunk at or below the top of the viewport.
+func (m *model) moveHunkCursor(back bool) bool {
+	if m.focus != paneDiff || len(m.diffHunks) == 0 {
+		return false
+	}
+	m.diffLineMode = false
+	switch {
+	case !m.diffHunkSelected:
+		m.diffHunkSelected = true
+		m.diffHunkCursor = len(m.diffHunks) - 1
+		for i, h := range m.diffHunks {
+			if h.start >= m.diffVP.YOffset {
+				m.diffHunkCursor = i
+				break
+			}
+		}
+	case back:
+		m.diffHunkCursor = max(0, m.diffHunkCursor-1)
+	default:
+		m.diffHunkCursor = min(len(m.diffHunks)-1, m.diffHunkCursor+1)
+	}
+	m.showSelectedHunk()
+	return true
+}
+
+// clearHunkSelection ends a line selection, or else drops the hunk cursor so
+// a/r/C act on whole files again.
+func (m *model) clearHunkSelection() bool {
+	switch {
+	case m.diffLineMode:
+		m.diffLineMode = false
+	case m.diffHunkSelected:
+		m.diffHunkSelected = false
+	default:
+		return false
+	}
+	m.renderDiffContent()
+	m.diffVP.SetContent(m.diffContent)
+	return true
+}
+
+// showSelectedHunk re-renders the diff and scrolls so the selected hunk's first
+// line is visible, keeping as much of the hunk on screen as fits.
+func (m *model) showSelectedHunk() {
+	m.renderDiffContent()
+	m.diffVP.SetContent(m.diffContent)
+	h, ok := m.selectedHunk()
+	if !ok {
+		return
+	}
+	end := h.start + len(h.lines)
+	if h.start < m.diffVP.YOffset || end > m.diffVP.YOffset+m.diffVP.Height {
+		m.diffVP.SetYOffset(h.start)
+	}
+}
+
+// applySelectedHunk stages (a, Worktree diff) or unstages (r, Staged diff) the
+// selected hunk or lines with git apply --cached.
+func (m *model) applySelectedHunk(key string) {
+	var args []string
+	var done string
+	var reverse bool
+	switch {
+	case key == "a" && m.diffMode == diffModeWorktree:
+		args, done = []string{"--cached"}, "staged"
+	case key == "r" && m.diffMode == diffModeStaged:
+		args, done, reverse = []string{"--cached", "-R"}, "unstaged", true
+	case key == "a":
+		log.Printf("hunk is already staged; switch to the Worktree diff (Space) to stage more")
+		return
+	default:
+		log.Printf("hunk is not staged; switch to the Staged diff (Space) to unstage")
+		return
+	}
+	h, ok := m.selectedPatchHunk(reverse)
+	if !ok {
+		log.Printf("no added or removed lines selected")
+		return
+	}
+	if err := gitApplyPatch(m.currentRepo(), h.patch(), args...); err != nil {
+		log.Printf("git: %v", err)
+		return
+	}
+	log.Printf("%s %s %s of %s", done, m.selectionNoun(), h.rangeLabel(), h.path)
+	m.refreshRepoStatusAfterGit()
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+}
+
+// openDiscardHunkConfirm asks before reverting the selected worktree hunk or lines.
+func (m *model) openDiscardHunkConfirm() {
+	if m.diffMode != diffModeWorktree {
+		log.Printf("only Worktree hunks can be discarded; unstage the hunk first")
+		return
+	}
+	h, ok := m.selectedPatchHunk(true)
+	if !ok {
+		log.Printf("no added or removed lines selected")
+		return
+	}
+	m.discardHunkConfirmOpen = true
+	m.discardHunkPending = h
+	m.deleteConfirmYes = false
+}
+
+// handleDiscardHunkConfirmKey processes keys while the discard-hunk confirmation is open.
+func (m *model) handleDiscardHunkConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		m.discardHunkConfirmOpen = false
+		return m, nil
+	case "enter":
+		m.discardHunkConfirmOpen = false
+		if !m.deleteConfirmYes {
+			return m, nil
+		}
+		h := m.discardHunkPending
+		if err := gitApplyPatch(m.currentRepo(), h.patch(), "-R"); err != nil {
+			log.Printf("git: %v", err)
+		} else {
+			log.Printf("discarded %s %s of %s", m.selectionNoun(), h.rangeLabel(), h.path)
+			m.refreshRepoStatusAfterGit()
+		}
+		m.diffNeedsRefresh = true
+		m.syncViewports()
+		return m, nil
+	default:
+		m.handleConfirmNavKey(msg)
+		return m, nil
+	}
+}
+
+// renderDiscardHunkConfirmOverlay previews the hunk that git apply -R would revert.
+func (m *model) renderDiscardHunkConfirmOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+	h := m.discardHunkPending
+
+	preview := make([]string, 0, discardHunkPreviewRows+1)
+	for i, line := range h.lines {
+		if i == discardHunkPreviewRows {
+			preview = append(preview, styleDim.Render(fmt.Sprintf("… %d more lines", len(h.lines)-i)))
+			break
+		}
+		preview = append(preview, styleDiffContent(truncateASCII(line, innerW)))
+	}
+	warn := warnBlock(innerW).Render("These lines go back to their staged (or committed) content. This cannot be undone.")
+	inner := strings.Join([]string{
+		styleBold.Render(fmt.Sprintf("Discard the %s from the working tree?", m.selectionNoun())), "",
+		"Repository", styleDim.Render(truncateASCII(m.currentRepo(), innerW)), "",
+		"Path (in repo)", styleDim.Render(truncateASCII(h.path, innerW)), "",
+		strings.Join(preview, "\n"), "",
+		warn, "",
+		deleteConfirmButtons(m.deleteConfirmYes), "",
+		deleteConfirmFooter(),
+	}, "\n")
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(inner))
+}
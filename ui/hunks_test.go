@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+const twoFileDiff = `diff --git a/a.txt b/a.txt
+index 1111111..2222222 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1,3 +1,3 @@
+-one
++ONE
+ two
+ three
+@@ -10,2 +10,3 @@ func x()
+ ten
+--- not a header
++eleven
+diff --git a/b.bin b/b.bin
+Binary files a/b.bin and b/b.bin differ
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+\ No newline at end of file
+`
+
+func TestParseDiffHunks(t *testing.T) {
+	hunks := parseDiffHunks(twoFileDiff)
+	if len(hunks) != 3 {
+		t.Fatalf("got %d hunks", len(hunks))
+	}
+	if hunks[0].path != "a.txt" || hunks[0].start != 4 || hunks[0].rangeLabel() != "-1,3 +1,3" {
+		t.Fatalf("hunk 0 = %+v", hunks[0])
+	}
+	if got := hunks[1].lines; len(got) != 4 || got[2] != "--- not a header" {
+		t.Fatalf("hunk 1 lines = %q", got)
+	}
+	wantPatch := "diff --git a/a.txt b/a.txt\nindex 1111111..2222222 100644\n--- a/a.txt\n+++ b/a.txt\n" +
+		"@@ -10,2 +10,3 @@ func x()\n ten\n--- not a header\n+eleven\n"
+	if got := hunks[1].patch(); got != wantPatch {
+		t.Fatalf("patch =\n%s", got)
+	}
+	if h := hunks[2]; h.path != "gone.txt" || len(h.header) != 4 || h.lines[len(h.lines)-1] != `\ No newline at end of file` {
+		t.Fatalf("deleted-file hunk = %+v", h)
+	}
+}
+
+func TestDiffHunkStageUnstageDiscard(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "r")
+	runGitT(t, filepath.Dir(repo), "init", "-q", repo)
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	file := filepath.Join(repo, "f.txt")
+	write := func(ls []string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(strings.Join(ls, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(lines)
+	runGitT(t, repo, "add", "f.txt")
+	runGitT(t, repo, "commit", "-qm", "base")
+	edited := append([]string(nil), lines...)
+	edited[1] = "fix"
+	edited[24] = "debug"
+	write(edited)
+
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.focus = paneDiff
+	m.syncViewports()
+	key := func(s string) {
+		t.Helper()
+		if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}); !handled {
+			t.Fatalf("%q not handled", s)
+		}
+	}
+
+	if len(m.diffHunks) != 2 {
+		t.Fatalf("got %d hunks", len(m.diffHunks))
+	}
+	key("]")
+	key("]")
+	if m.diffHunkCursor != 1 || !strings.Contains(m.diffPaneBorderTitle(), "hunk 2/2") {
+		t.Fatalf("cursor = %d, title %q", m.diffHunkCursor, m.diffPaneBorderTitle())
+	}
+	key("a")
+	if staged := runGitT(t, repo, "diff", "--cached"); !strings.Contains(staged, "+debug") || strings.Contains(staged, "+fix") {
+		t.Fatalf("staged diff:\n%s", staged)
+	}
+	if !m.diffHunkSelected || len(m.diffHunks) != 1 || m.diffHunkCursor != 0 {
+		t.Fatalf("after staging: selected=%v hunks=%d cursor=%d", m.diffHunkSelected, len(m.diffHunks), m.diffHunkCursor)
+	}
+
+	// Unstage it again from the Staged diff.
+	key(" ")
+	key("]")
+	key("r")
+	if staged := runGitT(t, repo, "diff", "--cached"); staged != "" {
+		t.Fatalf("expected nothing staged:\n%s", staged)
+	}
+
+	// Discard the first worktree hunk after confirming.
+	key(" ")
+	key("]")
+	key("C")
+	if !m.discardHunkConfirmOpen || !strings.Contains(m.View(), "Discard the hunk") {
+		t.Fatal("C should ask before discarding a hunk")
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := runGitT(t, repo, "diff"); strings.Contains(got, "+fix") || !strings.Contains(got, "+debug") {
+		t.Fatalf("worktree diff after discard:\n%s", got)
+	}
+
+	if !m.clearHunkSelection() || m.hunkOpsReady() {
+		t.Fatal("Esc should drop the hunk selection")
+	}
+}
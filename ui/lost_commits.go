@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// lostCommitsModalMaxRows caps how many lost commits the overlay lists at once.
+const lostCommitsModalMaxRows = 10
+
+// newRescueBranchInput builds the single-line branch name editor for the lost-commit overlay.
+func newRescueBranchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Branch: "
+	ti.CharLimit = 200
+	return ti
+}
+
+// currentLostCommits returns the lost reflog commits recorded for the selected repository.
+func (m *model) currentLostCommits() []scanner.LostCommit {
+	st, ok := m.repositories.Get(m.currentRepo())
+	if !ok {
+		return nil
+	}
+	return st.LostCommits
+}
+
+// openLostCommits shows the lost-commit overlay when the selected repo has any.
+func (m *model) openLostCommits() bool {
+	if len(m.currentLostCommits()) == 0 {
+		return false
+	}
+	m.lostCommitsOpen = true
+	m.lostCommitsCursor = 0
+	m.rescueBranchInput = newRescueBranchInput()
+	return true
+}
+
+// handleLostCommitsKey processes keys while the lost-commit overlay is open. With
+// the branch name input focused, keys edit the name; otherwise they move the cursor.
+func (m *model) handleLostCommitsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	lost := m.currentLostCommits()
+	if m.rescueBranchInput.Focused() {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.rescueBranchInput.Blur()
+			return m, nil
+		case "enter":
+			if m.lostCommitsCursor < len(lost) {
+				m.rescueLostCommit(lost[m.lostCommitsCursor], m.rescueBranchInput.Value())
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.rescueBranchInput, cmd = m.rescueBranchInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "L":
+		m.lostCommitsOpen = false
+	case "up", "k":
+		if m.lostCommitsCursor > 0 {
+			m.lostCommitsCursor--
+		}
+	case "down", "j":
+		if m.lostCommitsCursor < len(lost)-1 {
+			m.lostCommitsCursor++
+		}
+	case "enter", "b":
+		if m.lostCommitsCursor < len(lost) {
+			m.rescueBranchInput.SetValue("rescue/" + shortHash(lost[m.lostCommitsCursor].Hash))
+			m.rescueBranchInput.CursorEnd()
+			return m, m.rescueBranchInput.Focus()
+		}
+	}
+	return m, nil
+}
+
+// rescueLostCommit creates a branch at the lost commit, then refreshes the repo so
+// the commit drops out of the list once a ref reaches it.
+func (m *model) rescueLostCommit(lc scanner.LostCommit, name string) {
+	name = strings.TrimSpace(name)
+	repo := m.currentRepo()
+	if err := gitCreateBranchAt(repo, name, lc.Hash); err != nil {
+		log.Printf("git: %v", err)
+		return
+	}
+	log.Printf("created branch %q at %s", name, shortHash(lc.Hash))
+	m.rescueBranchInput.Blur()
+	m.refreshRepoStatusAfterGit()
+	remaining := m.currentLostCommits()
+	if m.currentRepo() != repo || len(remaining) == 0 {
+		m.lostCommitsOpen = false
+	}
+	m.lostCommitsCursor = min(m.lostCommitsCursor, max(0, len(remaining)-1))
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+}
+
+// renderLostCommitsOverlay lists unreachable reflog commits for the selected repository.
+func (m *model) renderLostCommitsOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+	lost := m.currentLostCommits()
+	if len(lost) == 0 {
+		return m.placeCenteredDimModal(roundedModal(boxW).Render("No lost commits."))
+	}
+
+	start := 0
+	if m.lostCommitsCursor >= lostCommitsModalMaxRows {
+		start = m.lostCommitsCursor - lostCommitsModalMaxRows + 1
+	}
+	end := min(len(lost), start+lostCommitsModalMaxRows)
+	rows := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		lc := lost[i]
+		line := fmt.Sprintf("%s  %-4s %-10s %s", shortHash(lc.Hash), relativeTime(lc.ReflogUnix),
+			truncateASCII(lc.Reflog, 10), lc.Subject)
+		line = truncateASCII(line, innerW)
+		if i == m.lostCommitsCursor {
+			line = styleSelRowFocused.Render(line)
+		}
+		rows = append(rows, line)
+	}
+
+	t := styleBold.Render(fmt.Sprintf("Lost commits (%d)", len(lost)))
+	repoLine := styleDim.Render(truncateASCII(m.currentRepo(), innerW))
+	hint := "No branch, tag, or remote-tracking ref reaches these reflog commits."
+	footer := styleDim.Render("↑/↓ select · Enter create rescue branch · Esc close")
+	parts := []string{t, "", repoLine, "", hint, "", strings.Join(rows, "\n"), ""}
+	if m.rescueBranchInput.Focused() {
+		m.rescueBranchInput.Width = max(1, innerW-len(m.rescueBranchInput.Prompt)-1)
+		parts = append(parts, m.rescueBranchInput.View(), "")
+		footer = styleDim.Render("Enter create branch · Esc back to list")
+	}
+	parts = append(parts, footer)
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(strings.Join(parts, "\n")))
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestLostCommitsKeyRequiresLostCommits(t *testing.T) {
+	m := newTestModel()
+	m.repoList = []string{"/repo"}
+	m.repositories.AddResult("/repo", scanner.RepoStatus{Branch: "main"})
+
+	_, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if handled || m.lostCommitsOpen {
+		t.Fatalf("L without lost commits should be ignored, handled=%v open=%v", handled, m.lostCommitsOpen)
+	}
+
+	m.repositories.AddResult("/repo", scanner.RepoStatus{
+		Branch: "main",
+		LostCommits: []scanner.LostCommit{
+			{Hash: "1111111111", Subject: "one", Reflog: "HEAD"},
+			{Hash: "2222222222", Subject: "two", Reflog: "main"},
+		},
+	})
+	_, _, handled = m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if !handled || !m.lostCommitsOpen {
+		t.Fatalf("L with lost commits should open overlay, handled=%v open=%v", handled, m.lostCommitsOpen)
+	}
+
+	m.handleLostCommitsKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.lostCommitsCursor != 1 {
+		t.Fatalf("cursor = %d, want 1", m.lostCommitsCursor)
+	}
+	m.handleLostCommitsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.rescueBranchInput.Focused() || m.rescueBranchInput.Value() != "rescue/22222222" {
+		t.Fatalf("enter should start naming with default, focused=%v value=%q",
+			m.rescueBranchInput.Focused(), m.rescueBranchInput.Value())
+	}
+	m.width, m.height = 100, 30
+	if out := m.renderLostCommitsOverlay(); !strings.Contains(out, "two") || !strings.Contains(out, "rescue/22222222") {
+		t.Fatalf("overlay should list commits and show the name input: %q", out)
+	}
+	m.handleLostCommitsKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.rescueBranchInput.Focused() || !m.lostCommitsOpen {
+		t.Fatal("esc while naming should return to the list")
+	}
+	m.handleLostCommitsKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.lostCommitsOpen {
+		t.Fatal("esc from the list should close the overlay")
+	}
+}
+
+func TestRescueLostCommitCreatesBranch(t *testing.T) {
+	repo := t.TempDir()
+	runGit := func(arg ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", arg...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", arg, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	runGit("init", "-b", "main")
+	runGit("config", "user.email", "u@x")
+	runGit("config", "user.name", "u")
+	runGit("commit", "--allow-empty", "-m", "base")
+	runGit("checkout", "--detach")
+	runGit("commit", "--allow-empty", "-m", "detached work")
+	lostHash := runGit("rev-parse", "HEAD")
+	runGit("checkout", "main")
+
+	cfg := &scanner.Config{}
+	cfg.LostCommits.MaxAge = time.Hour
+	rs, include, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !include || len(rs.LostCommits) != 1 || rs.LostCommits[0].Hash != lostHash {
+		t.Fatalf("expected one lost commit %s, include=%v lost=%+v", lostHash, include, rs.LostCommits)
+	}
+
+	m := newTestModel()
+	m.config = cfg
+	m.width, m.height = 100, 30
+	m.repoList = []string{repo}
+	m.repositories.AddResult(repo, rs)
+
+	m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	m.handleLostCommitsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m.rescueBranchInput.SetValue("saved")
+	m.handleLostCommitsKey(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if got := runGit("rev-parse", "refs/heads/saved"); got != lostHash {
+		t.Fatalf("saved branch at %s, want %s", got, lostHash)
+	}
+	if m.lostCommitsOpen {
+		t.Fatal("overlay should close once no lost commits remain")
+	}
+	if _, ok := m.repositories.Get(repo); ok {
+		t.Fatal("clean repo should drop out of the list after rescue")
+	}
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// mergedBranchesModalMaxRows caps how many merged branches the cleanup overlay lists at once.
+const mergedBranchesModalMaxRows = 12
+
+// mergedBranchItem is one deletable branch row in the cleanup overlay.
+type mergedBranchItem struct {
+	repo   string
+	branch scanner.LocalBranchRef
+}
+
+// mergedBranchesMsg delivers the result of the all-repositories merged branch scan.
+type mergedBranchesMsg struct {
+	gen   uint64
+	repos []scanner.RepoMergedBranches
+	err   error
+}
+
+// scanMergedBranchesCmd walks every repository under the scan roots, including
+// clean ones that are not in the repository list.
+func scanMergedBranchesCmd(config *scanner.Config, gen uint64) tea.Cmd {
+	return func() tea.Msg {
+		repos, err := scanner.ScanMergedBranches(context.Background(), config)
+		return mergedBranchesMsg{gen: gen, repos: repos, err: err}
+	}
+}
+
+// openMergedBranches shows the cleanup overlay scoped to the selected repository.
+func (m *model) openMergedBranches() {
+	m.mergedBranchesOpen = true
+	m.mergedBranchesAllRepos = false
+	m.mergedBranchesConfirmOpen = false
+	m.loadMergedBranchItems()
+}
+
+// loadMergedBranchItems rebuilds the overlay rows for the current scope. The
+// all-repositories scope scans asynchronously; see [mergedBranchesMsg].
+func (m *model) loadMergedBranchItems() tea.Cmd {
+	m.mergedBranchItems = nil
+	m.mergedBranchesCursor = 0
+	m.mergedBranchesSelected = make(map[int]bool)
+	m.mergedBranchesScanGen++
+	if m.mergedBranchesAllRepos {
+		m.mergedBranchesLoading = true
+		return scanMergedBranchesCmd(m.config, m.mergedBranchesScanGen)
+	}
+	m.mergedBranchesLoading = false
+	repo := m.currentRepo()
+	st, ok := m.repositories.Get(repo)
+	if !ok {
+		return nil
+	}
+	st.Branches = slices.Clone(st.Branches)
+	scanner.FillMergedInto(repo, st.Branches)
+	for _, lb := range st.MergedBranches(m.config) {
+		m.mergedBranchItems = append(m.mergedBranchItems, mergedBranchItem{repo: repo, branch: lb})
+	}
+	return nil
+}
+
+// handleMergedBranchesMsg fills the overlay once the all-repositories scan
+// finishes. Results from a scan that a later reload (Tab, or reopening the
+// overlay) superseded are dropped.
+func (m *model) handleMergedBranchesMsg(msg mergedBranchesMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.mergedBranchesScanGen || !m.mergedBranchesOpen || !m.mergedBranchesAllRepos {
+		return m, nil
+	}
+	m.mergedBranchesLoading = false
+	if msg.err != nil {
+		log.Printf("merged branch scan: %v", msg.err)
+		return m, nil
+	}
+	var items []mergedBranchItem
+	for _, r := range msg.repos {
+		for _, lb := range r.Branches {
+			items = append(items, mergedBranchItem{repo: r.Path, branch: lb})
+		}
+	}
+	m.mergedBranchItems = items
+	m.mergedBranchesSelected = make(map[int]bool)
+	m.mergedBranchesCursor = 0
+	return m, nil
+}
+
+// selectedMergedBranchCount returns how many rows are marked for deletion.
+func (m *model) selectedMergedBranchCount() int {
+	n := 0
+	for i := range m.mergedBranchItems {
+		if m.mergedBranchesSelected[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// handleMergedBranchesKey processes keys while the cleanup overlay (or its
+// delete confirmation) is open.
+func (m *model) handleMergedBranchesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.mergedBranchesConfirmOpen {
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			m.mergedBranchesConfirmOpen = false
+		case "enter":
+			m.mergedBranchesConfirmOpen = false
+			if m.deleteConfirmYes {
+				m.deleteSelectedMergedBranches()
+			}
+		default:
+			m.handleConfirmNavKey(msg)
+		}
+		return m, nil
+	}
+
+	items := m.mergedBranchItems
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "P":
+		m.mergedBranchesOpen = false
+	case "tab":
+		m.mergedBranchesAllRepos = !m.mergedBranchesAllRepos
+		return m, m.loadMergedBranchItems()
+	case "up", "k":
+		if m.mergedBranchesCursor > 0 {
+			m.mergedBranchesCursor--
+		}
+	case "down", "j":
+		if m.mergedBranchesCursor < len(items)-1 {
+			m.mergedBranchesCursor++
+		}
+	case " ", "space":
+		if m.mergedBranchesCursor < len(items) {
+			i := m.mergedBranchesCursor
+			m.mergedBranchesSelected[i] = !m.mergedBranchesSelected[i]
+		}
+	case "a":
+		all := m.selectedMergedBranchCount() < len(items)
+		for i := range items {
+			m.mergedBranchesSelected[i] = all
+		}
+	case "d", "enter":
+		if m.selectedMergedBranchCount() > 0 {
+			m.mergedBranchesConfirmOpen = true
+			m.deleteConfirmYes = false
+		}
+	}
+	return m, nil
+}
+
+// deleteSelectedMergedBranches runs git branch -d for each selected row, logs
+// the outcome, and refreshes every affected repository. Rows that git refused
+// to delete stay in the list.
+func (m *model) deleteSelectedMergedBranches() {
+	var kept []mergedBranchItem
+	touched := make(map[string]bool)
+	for i, it := range m.mergedBranchItems {
+		if !m.mergedBranchesSelected[i] {
+			kept = append(kept, it)
+			continue
+		}
+		if err := scanner.DeleteMergedBranch(it.repo, it.branch.Name); err != nil {
+			log.Printf("git: %v", err)
+			kept = append(kept, it)
+			continue
+		}
+		log.Printf("deleted branch %q in %s (was %s)", it.branch.Name, it.repo, shortHash(it.branch.TipHash))
+		touched[it.repo] = true
+	}
+	for repo := range touched {
+		if _, ok := m.repositories.Get(repo); ok {
+			m.refreshRepoStatus(repo)
+		}
+	}
+	m.mergedBranchItems = kept
+	m.mergedBranchesSelected = make(map[int]bool)
+	m.mergedBranchesCursor = min(m.mergedBranchesCursor, max(0, len(kept)-1))
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+}
+
+// renderMergedBranchesOverlay lists branches already merged into a remote default branch.
+func (m *model) renderMergedBranchesOverlay() string {
+	if m.mergedBranchesConfirmOpen {
+		return m.renderMergedBranchesConfirmOverlay()
+	}
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+	items := m.mergedBranchItems
+
+	scope := "Selected repository"
+	if m.mergedBranchesAllRepos {
+		scope = "All repositories under the scan roots"
+	}
+	t := styleBold.Render(fmt.Sprintf("Merged branches (%d)", len(items)))
+	parts := []string{t, "", styleDim.Render(scope), ""}
+
+	var body string
+	switch {
+	case m.mergedBranchesLoading:
+		body = "Scanning…"
+	case len(items) == 0:
+		body = "No local branches are fully merged into a remote default branch."
+	default:
+		start := 0
+		if m.mergedBranchesCursor >= mergedBranchesModalMaxRows {
+			start = m.mergedBranchesCursor - mergedBranchesModalMaxRows + 1
+		}
+		end := min(len(items), start+mergedBranchesModalMaxRows)
+		rows := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			it := items[i]
+			mark := "[ ]"
+			if m.mergedBranchesSelected[i] {
+				mark = "[x]"
+			}
+			// Truncate the plain text before styling so the cut never lands
+			// inside an escape sequence.
+			head := mark + " " + it.branch.Name
+			line := head + "  → " + it.branch.MergedInto
+			if m.mergedBranchesAllRepos {
+				line += "  " + it.repo
+			}
+			line = truncateWidth(line, innerW)
+			if rest, ok := strings.CutPrefix(line, head); ok {
+				line = head + styleDim.Render(rest)
+			}
+			if i == m.mergedBranchesCursor {
+				line = styleSelRowFocused.Render(line)
+			}
+			rows = append(rows, line)
+		}
+		body = strings.Join(rows, "\n")
+	}
+	footer := styleDim.Render("Space select · a all · d delete · Tab this repo/all repos · Esc close")
+	parts = append(parts, body, "", footer)
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(strings.Join(parts, "\n")))
+}
+
+// renderMergedBranchesConfirmOverlay asks once before deleting every selected branch.
+func (m *model) renderMergedBranchesConfirmOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+	n := m.selectedMergedBranchCount()
+	t := styleBold.Render(fmt.Sprintf("Delete %d merged branch(es)?", n))
+	var names []string
+	for i, it := range m.mergedBranchItems {
+		if !m.mergedBranchesSelected[i] {
+			continue
+		}
+		if len(names) == mergedBranchesModalMaxRows {
+			names = append(names, styleDim.Render(fmt.Sprintf("… and %d more", n-mergedBranchesModalMaxRows)))
+			break
+		}
+		names = append(names, truncateASCII(it.branch.Name, innerW))
+	}
+	warn := "Runs git branch -d for each; git refuses branches it does not consider merged."
+	btns := deleteConfirmButtons(m.deleteConfirmYes)
+	inner := strings.Join([]string{t, "", strings.Join(names, "\n"), "", warn, "", btns, "", deleteConfirmFooter()}, "\n")
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(inner))
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestMergedBranchesOverlayDeletesSelected(t *testing.T) {
+	repo, _ := cloneWithBareOrigin(t)
+	runGitT(t, repo, "branch", "old-a")
+	runGitT(t, repo, "branch", "old-b")
+
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.repoList = []string{repo}
+	m.repositories.AddResult(repo, rs)
+
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}}); !handled || !m.mergedBranchesOpen {
+		t.Fatalf("P should open the overlay, handled=%v", handled)
+	}
+	if len(m.mergedBranchItems) != 2 {
+		t.Fatalf("items = %+v, want old-a and old-b", m.mergedBranchItems)
+	}
+	m.width, m.height = 100, 30
+	if out := m.renderMergedBranchesOverlay(); !strings.Contains(out, "old-a") || !strings.Contains(out, "origin/main") {
+		t.Fatalf("overlay should list branches: %q", out)
+	}
+
+	m.handleMergedBranchesKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if m.mergedBranchesConfirmOpen {
+		t.Fatal("d with nothing selected should not ask for confirmation")
+	}
+	m.handleMergedBranchesKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m.handleMergedBranchesKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if !m.mergedBranchesConfirmOpen || m.deleteConfirmYes {
+		t.Fatal("d should open the confirmation with No highlighted")
+	}
+	m.handleMergedBranchesKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m.handleMergedBranchesKey(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if len(m.mergedBranchItems) != 1 || m.mergedBranchItems[0].branch.Name != "old-b" {
+		t.Fatalf("items after delete = %+v, want only old-b", m.mergedBranchItems)
+	}
+	if out := runGitT(t, repo, "branch", "--list", "old-a"); out != "" {
+		t.Fatalf("old-a should be deleted: %q", out)
+	}
+}
+
+func TestMergedBranchesScanDropsStaleResults(t *testing.T) {
+	m := newTestModel()
+	m.mergedBranchesOpen = true
+	m.mergedBranchesAllRepos = true
+	m.loadMergedBranchItems()
+	stale := m.mergedBranchesScanGen
+	m.loadMergedBranchItems()
+	msg := func(gen uint64, names ...string) mergedBranchesMsg {
+		r := scanner.RepoMergedBranches{Path: "/r"}
+		for _, n := range names {
+			r.Branches = append(r.Branches, scanner.LocalBranchRef{Name: n})
+		}
+		return mergedBranchesMsg{gen: gen, repos: []scanner.RepoMergedBranches{r}}
+	}
+
+	m.handleMergedBranchesMsg(msg(stale, "old"))
+	if len(m.mergedBranchItems) != 0 || !m.mergedBranchesLoading {
+		t.Fatalf("stale scan should be dropped: items=%+v loading=%v", m.mergedBranchItems, m.mergedBranchesLoading)
+	}
+	m.handleMergedBranchesMsg(msg(m.mergedBranchesScanGen, "a", "b"))
+	m.handleMergedBranchesMsg(msg(m.mergedBranchesScanGen, "a", "b"))
+	if len(m.mergedBranchItems) != 2 || m.mergedBranchesLoading {
+		t.Fatalf("items = %+v, want a and b once", m.mergedBranchItems)
+	}
+}
+
+func TestMergedBranchesOverlayTruncatesBeforeStyling(t *testing.T) {
+	m := newTestModel()
+	m.width = 60
+	m.mergedBranchesOpen = true
+	m.mergedBranchesAllRepos = true
+	m.mergedBranchItems = []mergedBranchItem{{
+		repo:   "/src/" + strings.Repeat("dépôt/", 20),
+		branch: scanner.LocalBranchRef{Name: "fix-ünïcode-branch", MergedInto: "origin/main"},
+	}}
+	out := m.renderMergedBranchesOverlay()
+	row := ""
+	for _, l := range strings.Split(out, "\n") {
+		if strings.Contains(l, "fix-ünïcode-branch") {
+			row = l
+		}
+	}
+	if !regexp.MustCompile("\x1b\\[[0-9;]*m  → origin/main  /src/[^\x1b]*…\x1b\\[0m").MatchString(row) {
+		t.Fatalf("row should be cut with an ellipsis inside the dim style: %q", row)
+	}
+	if plain := ansiSGR.ReplaceAllString(row, ""); strings.Contains(plain, "\x1b") || !utf8.ValidString(plain) {
+		t.Fatalf("row cut inside an escape or a rune: %q", row)
+	}
+}
@@ -6,7 +6,10 @@ import (
 
 	cspinner "github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/boyvinall/dirtygit/scanner"
 )
@@ -20,6 +23,8 @@ const (
 	paneBranches
 	paneDiff
 	paneLog
+	// paneStash takes the Branches slot below Status when shown (key $).
+	paneStash
 )
 
 // tickMsg drives periodic polling while scans are running.
@@ -80,18 +85,67 @@ type model struct {
 	width  int
 	height int
 
-	repositories  *scanner.MultiGitStatus
-	repoList      []string
-	repoScrollTop int // first visible repo index when the list exceeds pane height
-	cursor        int
-	focus         pane
+	repositories *scanner.MultiGitStatus
+	repoList     []string
+	// showBehind lists repos that are only behind their remote (key b); see visibleRepoPaths.
+	showBehind bool
+	// repoSort orders the repository list (keys o / O).
+	repoSort repoSortMode
+	// repoGroup groups the list under headers (key g); collapsedGroups holds
+	// the group keys folded with Space, kept across rescans, and
+	// repoGroupSizes the repository count per group for the headers.
+	repoGroup       repoGroupMode
+	collapsedGroups map[string]bool
+	repoGroupSizes  map[string]int
+	// filters holds the / filter query per pane (repo, status, branches);
+	// filterEditing is true while typing the query for filterPane.
+	// repoFilterHits records how each listed repo matched, for highlighting.
+	filters        map[pane]string
+	filterEditing  bool
+	filterPane     pane
+	repoFilterHits map[string]repoFilterHit
+	repoScrollTop  int // first visible repo index when the list exceeds pane height
+	cursor         int
+	focus          pane
 
 	statusTable        table.Model
 	statusPaths        []string
 	statusFileSelected bool
-	branchTable        table.Model
-	diffMode           diffMode
-	diffNeedsRefresh   bool
+	// statusTree shows Status grouped by directory (key T); statusDirRows marks
+	// which statusPaths rows are directories, statusDirEntries holds the entries
+	// listed under each directory row, and statusCollapsed holds the collapsed
+	// directories per repository.
+	statusTree       bool
+	statusDirRows    []bool
+	statusDirEntries map[string][]scanner.PorcelainEntry
+	statusCollapsed  map[string]map[string]bool
+	branchTable      table.Model
+	// branchRows are the branches behind the Branches table rows, in row order;
+	// unpushed commit rows repeat their branch (the lost-commits row has no entry).
+	branchRows []scanner.LocalBranchRef
+	// branchRowDetails parallels branchRows: the remote copy or unpushed commit
+	// listed on that row, zero on branch rows. branchExpanded holds the branches
+	// (repo and name) expanded with Space, and branchCommitCache the unpushed
+	// commits loaded for them.
+	branchRowDetails  []branchRowDetail
+	branchExpanded    map[string]bool
+	branchCommitCache map[string][]scanner.UnpushedCommit
+	// diffCommit is the commit the Diff pane shows with git show after an
+	// unpushed commit row is selected in the focused Branches pane.
+	diffCommit string
+	// diffCompare is the local/remote pair the Diff pane compares after a remote
+	// row is selected in the focused Branches pane; diffCompareReverse shows
+	// the remote's side instead of the local one (key R).
+	diffCompare        branchCompare
+	diffCompareReverse bool
+	// diffStash is the stash commit the Diff pane shows after a Stash pane row
+	// is selected.
+	diffStash string
+	// diffDetailRepo is the repository diffCommit, diffCompare or diffStash
+	// belongs to; they stay while Diff or Log has focus (see keepDiffDetail).
+	diffDetailRepo   string
+	diffMode         diffMode
+	diffNeedsRefresh bool
 	// repoNavSettleGen increments on each repo list movement; only the matching
 	// repoNavSettledMsg applies heavy pane updates so rapid key repeat debounces.
 	repoNavSettleGen uint64
@@ -100,6 +154,28 @@ type model struct {
 	diffRequestGen uint64
 	diffContent    string
 	diffErr        error
+	// diffRaw is the unstyled git diff output behind diffContent.
+	diffRaw string
+	// diffStyled is diffRaw as styleDiff rendered it for diffStyledOpts; it is
+	// reused while diffRaw (held in diffStyledRaw) and the options stay the same.
+	diffStyled     string
+	diffStyledRaw  string
+	diffStyledOpts diffStyleOptions
+	// diffTarget identifies the repo, path and mode diffRaw was loaded for.
+	diffTarget string
+	// diffHunks are the hunks parsed from diffRaw.
+	diffHunks []diffHunk
+	// diffHunkSelected shows the hunk cursor ([ / ]); a r C then act on that hunk.
+	diffHunkSelected bool
+	// diffHunkCursor indexes the selected hunk in diffHunks.
+	diffHunkCursor int
+	// diffLineMode selects a line range inside the selected hunk (v); a r C then
+	// act on those lines.
+	diffLineMode bool
+	// diffLineAnchor and diffLineCursor bound the line selection, as indexes into
+	// the hunk's lines (0 is the "@@" line).
+	diffLineAnchor int
+	diffLineCursor int
 	diffVP         viewport.Model
 	logVP          viewport.Model
 
@@ -113,19 +189,108 @@ type model struct {
 	err error
 
 	helpOpen bool
+	// whyOpen explains why the selected repository is listed (key w).
+	whyOpen bool
 	// deleteRepoConfirmOpen shows the recursive-delete confirmation for the selected repository path.
 	deleteRepoConfirmOpen bool
 	// deleteStatusFileConfirmOpen asks before deleting the selected status path from disk.
 	deleteStatusFileConfirmOpen bool
 	// deleteStatusFilePendingRel is the repo-relative path pending confirmation (git status form).
 	deleteStatusFilePendingRel string
+	// deleteStatusFilePendingPaths are the entries listed under a pending
+	// directory row, removed on confirmation; nil means the pending path alone.
+	deleteStatusFilePendingPaths []string
 	// checkoutStatusFileConfirmOpen asks before git checkout HEAD -- path.
 	checkoutStatusFileConfirmOpen bool
 	// checkoutStatusFilePendingRel is the repo-relative path pending checkout confirmation.
 	checkoutStatusFilePendingRel string
+	// checkoutStatusFilePendingPaths are the paths restored on confirmation, as
+	// for deleteStatusFilePendingPaths.
+	checkoutStatusFilePendingPaths []string
+	// discardHunkConfirmOpen asks before reverting the selected worktree hunk.
+	discardHunkConfirmOpen bool
+	// discardHunkPending is the hunk awaiting discard confirmation.
+	discardHunkPending diffHunk
 	// deleteConfirmYes is true when "Yes" is highlighted; default is false ("No" highlighted).
 	deleteConfirmYes bool
 
+	// lostCommitsOpen shows unreachable reflog commits for the selected repository (key L).
+	lostCommitsOpen bool
+	// lostCommitsCursor is the highlighted row in the lost-commit overlay.
+	lostCommitsCursor int
+	// rescueBranchInput edits the new branch name; focused while naming a rescue branch.
+	rescueBranchInput textinput.Model
+
+	// commitOpen shows the commit message overlay for the selected repository (key c).
+	commitOpen bool
+	// commitInput is the multi-line commit message editor.
+	commitInput textarea.Model
+	// commitAmend runs git commit --amend instead of creating a new commit.
+	commitAmend bool
+	// commitErr is the last commit failure, shown in the overlay.
+	commitErr string
+
+	// pushOpen shows the push overlay for the Branches pane (key p).
+	pushOpen bool
+	// pushRemotes are the repository's remotes; pushRemoteCursor picks the target.
+	pushRemotes      []string
+	pushRemoteCursor int
+	// pushAll pushes every unpushed branch instead of the selected one.
+	pushAll bool
+	// pushing is true while git push runs; pushCh delivers its output.
+	pushing bool
+	pushCh  chan tea.Msg
+	// pushProgress is the newest line of git push output.
+	pushProgress string
+
+	// branchMenuOpen shows the actions menu for branchMenuBranch (key m in Branches).
+	branchMenuOpen   bool
+	branchMenuBranch scanner.LocalBranchRef
+	branchMenuCursor int
+	// branchInput edits a branch or upstream name for branchInputAction.
+	branchInput       textinput.Model
+	branchInputAction string
+	// branchForceDeleteConfirm asks before git branch -D; branchForceDeleteUnique
+	// counts the branch's commits no other branch, tag or remote has (-1 when
+	// unknown), which the delete would orphan.
+	branchForceDeleteConfirm bool
+	branchForceDeleteUnique  int
+
+	// stashShown puts the Stash pane below Status instead of Branches (key $).
+	// stashTable lists stashEntries for the selected repository; stashCache
+	// holds them per repository stash count and newest entry.
+	stashShown   bool
+	stashTable   table.Model
+	stashEntries []scanner.StashEntry
+	stashCache   map[string][]scanner.StashEntry
+	// stashCreateOpen shows the new-stash overlay (key n in Stash); stashInput
+	// edits the message and stashUntracked adds --include-untracked.
+	stashCreateOpen bool
+	stashInput      textinput.Model
+	stashUntracked  bool
+	// stashDropConfirmOpen asks before git stash drop of stashDropPending.
+	stashDropConfirmOpen bool
+	stashDropPending     scanner.StashEntry
+
+	// mergedBranchesOpen shows the merged-branch cleanup overlay (key P).
+	mergedBranchesOpen bool
+	// mergedBranchesAllRepos widens the overlay from the selected repository to
+	// every repository under the scan roots.
+	mergedBranchesAllRepos bool
+	// mergedBranchesLoading is true while the all-repositories scan runs.
+	mergedBranchesLoading bool
+	// mergedBranchesScanGen increments each time the overlay rows reload; only
+	// the all-repositories scan started by the latest reload fills them.
+	mergedBranchesScanGen uint64
+	// mergedBranchItems are the deletable rows for the current scope.
+	mergedBranchItems []mergedBranchItem
+	// mergedBranchesCursor is the highlighted row in the cleanup overlay.
+	mergedBranchesCursor int
+	// mergedBranchesSelected marks rows (by index) to delete.
+	mergedBranchesSelected map[int]bool
+	// mergedBranchesConfirmOpen asks once before deleting every selected branch.
+	mergedBranchesConfirmOpen bool
+
 	zoomed     bool
 	zoomTarget pane // which pane is fullscreen when zoomed
 
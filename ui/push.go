@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// pushTarget is one branch to push and whether it needs an upstream set.
+type pushTarget struct {
+	branch      string
+	setUpstream bool
+}
+
+// pushProgressMsg carries one line of git push output. Lines git ends with a
+// carriage return are in-place progress updates: they replace the overlay's
+// progress line but are not logged.
+type pushProgressMsg struct {
+	line   string
+	update bool
+}
+
+// pushDoneMsg is sent once every branch has been pushed, or at the first failure.
+type pushDoneMsg struct {
+	repo string
+	err  error
+}
+
+// selectedBranch returns the branch under the Branches pane cursor.
+func (m *model) selectedBranch() (scanner.LocalBranchRef, bool) {
+	i := m.branchTable.Cursor()
+	if i < 0 || i >= len(m.branchRows) {
+		return scanner.LocalBranchRef{}, false
+	}
+	return m.branchRows[i], true
+}
+
+// needsPush reports whether pushing the branch would send anything: commits no
+// remote has, a same-named remote branch that is behind, or no remote copy at all.
+// The HEAD row of a detached repo never does: there is no branch to push.
+func needsPush(st scanner.RepoStatus, lb scanner.LocalBranchRef) bool {
+	if isDetachedHead(st, lb) {
+		return false
+	}
+	if lb.UnpushedCount > 0 {
+		return true
+	}
+	onRemote := false
+	for _, loc := range lb.Locations {
+		if loc.Name == "local" || !loc.Exists {
+			continue
+		}
+		onRemote = true
+		if loc.Outgoing > 0 {
+			return true
+		}
+	}
+	return !onRemote
+}
+
+// isDetachedHead reports whether lb is the row the scanner adds for a detached
+// HEAD rather than a local branch.
+func isDetachedHead(st scanner.RepoStatus, lb scanner.LocalBranchRef) bool {
+	return st.Detached && lb.Name == "HEAD"
+}
+
+// unpushedBranches returns the shown branches that need a push.
+func (m *model) unpushedBranches() []scanner.LocalBranchRef {
+	st, ok := m.repositories.Get(m.currentRepo())
+	if !ok {
+		return nil
+	}
+	var out []scanner.LocalBranchRef
+	for _, lb := range st.FilteredBranches {
+		if needsPush(st, lb) {
+			out = append(out, lb)
+		}
+	}
+	return out
+}
+
+// upstreamRemote is the remote part of a refs/remotes/<remote>/<branch> upstream.
+func upstreamRemote(upstream string) string {
+	rest, ok := strings.CutPrefix(upstream, "refs/remotes/")
+	if !ok {
+		return ""
+	}
+	remote, _, _ := strings.Cut(rest, "/")
+	return remote
+}
+
+// openPush shows the push overlay for the Branches pane. The remote defaults to
+// the selected branch's upstream remote, then origin, then the first remote.
+// The cursor must be on a branch; Tab in the overlay widens it to all branches.
+func (m *model) openPush() bool {
+	if m.focus != paneBranches || m.err != nil {
+		return false
+	}
+	lb, ok := m.selectedBranch()
+	if !ok {
+		log.Printf("push: no branch selected")
+		return true
+	}
+	if st, _ := m.repositories.Get(m.currentRepo()); isDetachedHead(st, lb) {
+		log.Printf("push: HEAD is detached; check out a branch to push")
+		return true
+	}
+	out, err := runGitInRepo(m.currentRepo(), "remote")
+	if err != nil {
+		log.Printf("git: %v", err)
+		return true
+	}
+	if out == "" {
+		log.Printf("push: %s has no remotes", m.currentRepo())
+		return true
+	}
+	m.pushRemotes = strings.Split(out, "\n")
+	m.pushRemoteCursor = 0
+	want := upstreamRemote(lb.Upstream)
+	if want == "" {
+		want = "origin"
+	}
+	for i, r := range m.pushRemotes {
+		if r == want {
+			m.pushRemoteCursor = i
+		}
+	}
+	m.pushAll = false
+	m.pushOpen = true
+	m.pushProgress = ""
+	return true
+}
+
+// pushTargets lists what Enter would push for the current scope and remote. A
+// branch gets -u when it has no upstream yet.
+func (m *model) pushTargets() []pushTarget {
+	var branches []scanner.LocalBranchRef
+	if m.pushAll {
+		branches = m.unpushedBranches()
+	} else if lb, ok := m.selectedBranch(); ok {
+		branches = []scanner.LocalBranchRef{lb}
+	}
+	targets := make([]pushTarget, 0, len(branches))
+	for _, lb := range branches {
+		targets = append(targets, pushTarget{branch: lb.Name, setUpstream: lb.Upstream == ""})
+	}
+	return targets
+}
+
+// handlePushKey processes keys while the push overlay is open. While git runs,
+// only Ctrl+C is honoured.
+func (m *model) handlePushKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+	if m.pushing {
+		return m, nil
+	}
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "p":
+		m.pushOpen = false
+	case "up", "k":
+		m.pushRemoteCursor = max(0, m.pushRemoteCursor-1)
+	case "down", "j":
+		m.pushRemoteCursor = min(len(m.pushRemotes)-1, m.pushRemoteCursor+1)
+	case "tab", "a":
+		m.pushAll = !m.pushAll
+	case "enter":
+		targets := m.pushTargets()
+		if len(targets) == 0 {
+			return m, nil
+		}
+		m.pushing = true
+		m.pushProgress = ""
+		m.pushCh = make(chan tea.Msg, 64)
+		go runPush(m.currentRepo(), m.pushRemotes[m.pushRemoteCursor], targets, m.pushCh)
+		return m, waitPushMsg(m.pushCh)
+	}
+	return m, nil
+}
+
+// waitPushMsg delivers the next message from a running push.
+func waitPushMsg(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// runPush pushes each target to remote in turn, streaming git's output to ch and
+// finishing with a pushDoneMsg.
+func runPush(repo, remote string, targets []pushTarget, ch chan<- tea.Msg) {
+	for _, t := range targets {
+		args := []string{"push", "--progress"}
+		if t.setUpstream {
+			args = append(args, "--set-upstream")
+		}
+		args = append(args, remote, "refs/heads/"+t.branch+":refs/heads/"+t.branch)
+		ch <- pushProgressMsg{line: "git " + strings.Join(args, " ")}
+		if err := streamGit(repo, args, ch); err != nil {
+			ch <- pushDoneMsg{repo: repo, err: fmt.Errorf("push %s to %s: %w", t.branch, remote, err)}
+			return
+		}
+	}
+	ch <- pushDoneMsg{repo: repo}
+}
+
+// streamGit runs git in repo, sending each output line to ch as it arrives.
+// Prompts are disabled so a missing credential fails instead of hanging the UI.
+func streamGit(repo string, args []string, ch chan<- tea.Msg) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repo
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		done <- err
+	}()
+	sc := bufio.NewScanner(pr)
+	sc.Split(scanProgressLines)
+	for sc.Scan() {
+		tok := sc.Text()
+		line := strings.TrimRight(tok, "\r\n")
+		if strings.TrimSpace(line) != "" {
+			ch <- pushProgressMsg{line: line, update: strings.HasSuffix(tok, "\r")}
+		}
+	}
+	// Keep git from blocking on a full pipe if scanning stopped early.
+	_, _ = io.Copy(io.Discard, pr)
+	return <-done
+}
+
+// scanProgressLines is a bufio.SplitFunc that ends tokens at \r as well as \n,
+// keeping the terminator so callers can tell progress updates from full lines.
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// handlePushProgress logs finished output lines and keeps the newest line for the overlay.
+func (m *model) handlePushProgress(msg pushProgressMsg) (tea.Model, tea.Cmd) {
+	m.pushProgress = msg.line
+	if !msg.update {
+		log.Printf("push: %s", msg.line)
+	}
+	return m, waitPushMsg(m.pushCh)
+}
+
+// handlePushDone closes the overlay and re-reads the repository's branch status.
+func (m *model) handlePushDone(msg pushDoneMsg) (tea.Model, tea.Cmd) {
+	m.pushing = false
+	m.pushOpen = false
+	m.pushCh = nil
+	if msg.err != nil {
+		log.Printf("git: %v", msg.err)
+	} else {
+		log.Printf("push: done")
+	}
+	m.refreshRepoStatus(msg.repo)
+	m.syncViewports()
+	return m, nil
+}
+
+// renderPushOverlay shows the remote picker, the branches in scope, and progress.
+func (m *model) renderPushOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+
+	scope := "Selected branch"
+	if m.pushAll {
+		scope = "All unpushed branches"
+	}
+	parts := []string{
+		styleBold.Render("Push"), "",
+		styleDim.Render(truncateASCII(m.currentRepo(), innerW)), "",
+		"Remote",
+	}
+	for i, r := range m.pushRemotes {
+		line := "  " + truncateASCII(r, innerW-2)
+		if i == m.pushRemoteCursor {
+			line = styleSelRowFocused.Render(line)
+		}
+		parts = append(parts, line)
+	}
+	parts = append(parts, "", scope)
+	targets := m.pushTargets()
+	for _, t := range targets {
+		line := "  " + t.branch
+		if t.setUpstream {
+			line += styleDim.Render("  (sets upstream)")
+		}
+		parts = append(parts, line)
+	}
+	if len(targets) == 0 {
+		parts = append(parts, styleDim.Render("  No branch to push."))
+	}
+	footer := "↑/↓ remote · Tab selected / all unpushed · Enter push · Esc cancel"
+	if m.pushing {
+		parts = append(parts, "", truncateASCII(m.pushProgress, innerW))
+		footer = "Pushing…"
+	}
+	parts = append(parts, "", styleDim.Render(footer))
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(strings.Join(parts, "\n")))
+}
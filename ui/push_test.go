@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"bufio"
+	"log"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestScanProgressLines(t *testing.T) {
+	sc := bufio.NewScanner(strings.NewReader("Counting: 50%\rCounting: 100%, done.\nTo ../o.git\n * [new branch]"))
+	sc.Split(scanProgressLines)
+	var got []string
+	for sc.Scan() {
+		got = append(got, sc.Text())
+	}
+	want := []string{"Counting: 50%\r", "Counting: 100%, done.\n", "To ../o.git\n", " * [new branch]"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("tokens = %q", got)
+	}
+}
+
+func TestPushBranchesToBareRemote(t *testing.T) {
+	repo, origin := cloneWithBareOrigin(t)
+	runGitT(t, repo, "commit", "-q", "--allow-empty", "-m", "on main")
+	runGitT(t, repo, "checkout", "-q", "-b", "feature")
+	runGitT(t, repo, "commit", "-q", "--allow-empty", "-m", "on feature")
+
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.focus = paneBranches
+	m.syncViewports()
+	prevLog := log.Writer()
+	log.SetOutput(m.logBuf)
+	defer log.SetOutput(prevLog)
+
+	// drain runs the push to completion, feeding each message back into Update.
+	drain := func(cmd tea.Cmd) {
+		t.Helper()
+		for cmd != nil {
+			_, cmd = m.Update(cmd())
+		}
+	}
+
+	for i, lb := range m.branchRows {
+		if lb.Name == "feature" {
+			m.branchTable.SetCursor(i)
+		}
+	}
+	if lb, ok := m.selectedBranch(); !ok || lb.Name != "feature" {
+		t.Fatalf("selected branch = %+v (rows %d)", lb, len(m.branchRows))
+	}
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")}); !handled || !m.pushOpen {
+		t.Fatal("p should open the push overlay")
+	}
+	if m.pushRemotes[m.pushRemoteCursor] != "origin" || !strings.Contains(m.View(), "(sets upstream)") {
+		t.Fatalf("remote = %q, view:\n%s", m.pushRemotes[m.pushRemoteCursor], m.View())
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	drain(cmd)
+	if m.pushOpen || m.pushing {
+		t.Fatal("the overlay should close once the push finishes")
+	}
+	if got := strings.TrimSpace(runGitT(t, repo, "rev-parse", "--abbrev-ref", "feature@{upstream}")); got != "origin/feature" {
+		t.Fatalf("upstream = %q", got)
+	}
+	logText := m.logBuf.String()
+	if !strings.Contains(logText, "push: git push --progress --set-upstream origin refs/heads/feature:refs/heads/feature") ||
+		!strings.Contains(logText, "[new branch]") || !strings.Contains(logText, "push: done") {
+		t.Fatalf("log:\n%s", logText)
+	}
+
+	// Tab widens to every unpushed branch; only main is left. Its commit is on
+	// origin/feature now, but origin/main is still behind.
+	m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyTab})
+	if targets := m.pushTargets(); len(targets) != 1 || targets[0].branch != "main" || targets[0].setUpstream {
+		t.Fatalf("targets = %+v", targets)
+	}
+	_, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	drain(cmd)
+	if local, remote := runGitT(t, repo, "rev-parse", "main"), runGitT(t, origin, "rev-parse", "main"); local != remote {
+		t.Fatalf("origin main = %s, want %s", remote, local)
+	}
+	if _, ok := m.repositories.Get(repo); ok {
+		t.Fatal("the repo should drop out of the list once nothing is unpushed")
+	}
+}
+
+func TestPushAllSkipsDetachedHead(t *testing.T) {
+	repo, origin := cloneWithBareOrigin(t)
+	runGitT(t, repo, "checkout", "-q", "-b", "feature")
+	runGitT(t, repo, "commit", "-q", "--allow-empty", "-m", "on feature")
+	runGitT(t, repo, "checkout", "-q", "--detach", "main")
+
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.focus = paneBranches
+	m.syncViewports()
+	prevLog := log.Writer()
+	log.SetOutput(m.logBuf)
+	defer log.SetOutput(prevLog)
+
+	if lb, ok := m.selectedBranch(); !ok || lb.Name != "HEAD" {
+		t.Fatalf("selected branch = %+v, want the detached HEAD row", lb)
+	}
+	if !m.openPush() || m.pushOpen || !strings.Contains(m.logBuf.String(), "push: HEAD is detached") {
+		t.Fatalf("p on the detached HEAD row should only log; log:\n%s", m.logBuf.String())
+	}
+
+	m.branchTable.SetCursor(1)
+	m.openPush()
+	m.handleKey(tea.KeyMsg{Type: tea.KeyTab})
+	if targets := m.pushTargets(); len(targets) != 1 || targets[0].branch != "feature" {
+		t.Fatalf("targets = %+v, want feature alone", targets)
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	for cmd != nil {
+		_, cmd = m.Update(cmd())
+	}
+	if local, remote := runGitT(t, repo, "rev-parse", "feature"), runGitT(t, origin, "rev-parse", "feature"); local != remote {
+		t.Fatalf("origin feature = %s, want %s", remote, local)
+	}
+}
+
+func TestPushNeedsSelectedBranch(t *testing.T) {
+	m := newTestModel()
+	m.focus = paneBranches
+	prevLog := log.Writer()
+	log.SetOutput(m.logBuf)
+	defer log.SetOutput(prevLog)
+	if !m.openPush() || m.pushOpen {
+		t.Fatal("p off a branch row should be handled without opening the overlay")
+	}
+	if !strings.Contains(m.logBuf.String(), "push: no branch selected") {
+		t.Fatalf("log:\n%s", m.logBuf.String())
+	}
+}
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// repoColsMinPathWidth is the narrowest the path may get before summary
+// columns are dropped, rightmost first.
+const repoColsMinPathWidth = 30
+
+// repoColumnMaxWidth caps any one summary column (e.g. long branch names).
+const repoColumnMaxWidth = 20
+
+// repoColumnCell renders the cell for column name, "" when it has nothing to show.
+func repoColumnCell(name string, st scanner.RepoStatus) string {
+	switch name {
+	case scanner.RepoColumnStatus:
+		return porcelainCounts(st.Porcelain)
+	case scanner.RepoColumnBranch:
+		if st.Detached {
+			return "@" + shortHash(st.Branch)
+		}
+		return st.Branch
+	case scanner.RepoColumnSync:
+		var parts []string
+		if n := st.UnpushedCommitCount(); n > 0 {
+			parts = append(parts, fmt.Sprintf("↑%d", n))
+		}
+		if n, _ := st.Behind(); n > 0 {
+			parts = append(parts, fmt.Sprintf("↓%d", n))
+		}
+		return strings.Join(parts, "")
+	case scanner.RepoColumnStash:
+		if st.StashCount > 0 {
+			return fmt.Sprintf("$%d", st.StashCount)
+		}
+	case scanner.RepoColumnDirty:
+		if st.DirtySinceUnix > 0 && len(st.Porcelain.Entries) > 0 {
+			return "dirty " + relativeTime(st.DirtySinceUnix)
+		}
+	case scanner.RepoColumnUnpushed:
+		if t := st.OldestUnpushedUnix(); t > 0 {
+			return "unpushed " + relativeTime(t)
+		}
+	}
+	return ""
+}
+
+// porcelainCounts summarizes entries as "+staged ~modified ?untracked",
+// leaving out zero counts.
+func porcelainCounts(p scanner.PorcelainStatus) string {
+	staged, modified, untracked := countEntries(p.Entries)
+	var parts []string
+	for _, c := range []struct {
+		mark string
+		n    int
+	}{{"+", staged}, {"~", modified}, {"?", untracked}} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d", c.mark, c.n))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// countEntries counts the entries with staged changes, with worktree changes,
+// and untracked. A file with both staged and worktree changes counts in both.
+func countEntries(entries []scanner.PorcelainEntry) (staged, modified, untracked int) {
+	for _, e := range entries {
+		if e.Staging == '?' {
+			untracked++
+			continue
+		}
+		if e.Staging != ' ' && e.Staging != '!' {
+			staged++
+		}
+		if e.Worktree != ' ' && e.Worktree != '!' {
+			modified++
+		}
+	}
+	return staged, modified, untracked
+}
+
+// repoColumnNames returns the configured summary columns, or all of them.
+func (m *model) repoColumnNames() []string {
+	if m.config != nil && len(m.config.RepoList.Columns) > 0 {
+		return m.config.RepoList.Columns
+	}
+	return scanner.RepoColumns
+}
+
+// repoColumn is one summary column and its width in the current layout.
+type repoColumn struct {
+	name  string
+	width int
+}
+
+// repoColumnLayout sizes the summary columns to their widest cell across the
+// listed repositories and drops trailing columns that would leave the path
+// narrower than repoColsMinPathWidth in a row width cells wide.
+func (m *model) repoColumnLayout(width int) []repoColumn {
+	var cols []repoColumn
+	for _, name := range m.repoColumnNames() {
+		w := 0
+		for _, p := range m.repoList {
+			if st, ok := m.repositories.Get(p); ok {
+				w = max(w, lipgloss.Width(repoColumnCell(name, st)))
+			}
+		}
+		if w > 0 {
+			cols = append(cols, repoColumn{name: name, width: min(w, repoColumnMaxWidth)})
+		}
+	}
+	used := 0
+	for _, c := range cols {
+		used += c.width + 2
+	}
+	for len(cols) > 0 && width-used < repoColsMinPathWidth {
+		used -= cols[len(cols)-1].width + 2
+		cols = cols[:len(cols)-1]
+	}
+	return cols
+}
+
+// repoListRow renders one row: a group header, or the repository path (and
+// any suffix) padded or truncated to width, followed by the summary columns.
+// Grouped repositories are indented under their header. With highlight,
+// characters matched by the / filter are styled and repositories it does not
+// match are dimmed.
+func (m *model) repoListRow(path string, width int, cols []repoColumn, highlight bool) string {
+	if isGroupRow(path) {
+		return m.groupHeaderLabel(path)
+	}
+	indent := ""
+	if m.repoGroup != repoGroupNone {
+		indent = "  "
+	}
+	hit := m.repoFilterHits[path]
+	label := indent + path + m.repoListSuffix(path)
+	if hit.via != "" {
+		label += "  (" + hit.via + ")"
+	}
+	if len(cols) > 0 {
+		st, _ := m.repositories.Get(path)
+		var cells strings.Builder
+		for _, c := range cols {
+			cell := truncateWidth(repoColumnCell(c.name, st), c.width)
+			cells.WriteString("  " + strings.Repeat(" ", c.width-lipgloss.Width(cell)) + cell)
+		}
+		pathW := max(0, width-lipgloss.Width(cells.String()))
+		label = truncateWidth(label, pathW)
+		label += strings.Repeat(" ", pathW-lipgloss.Width(label)) + cells.String()
+	}
+	if highlight {
+		if m.repoFilterMiss(path) {
+			return styleDim.Render(label)
+		}
+		label = highlightRunes(label, hit.pathIdx, len(indent))
+	}
+	return label
+}
+
+// repoColumnLegend explains the summary column markers for the help panel.
+var repoColumnLegend = []string{
+	"Repository columns (repolist.columns in config):",
+	"  status    +N staged, ~N modified, ?N untracked paths",
+	"  branch    current branch, or @hash when HEAD is detached",
+	"  sync      ↑N unpushed commits, ↓N incoming commits (as of the last fetch)",
+	"  stash     $N stash entries",
+	"  dirty     age of the oldest uncommitted change",
+	"  unpushed  age of the oldest unpushed commit",
+}
+
+// truncateWidth shortens s to at most w terminal cells, ending with "…" when cut.
+func truncateWidth(s string, w int) string {
+	if lipgloss.Width(s) <= w {
+		return s
+	}
+	if w <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		rw := lipgloss.Width(string(r))
+		if used+rw > w-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += rw
+	}
+	return b.String() + "…"
+}
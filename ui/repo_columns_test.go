@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestRepoColumnCells(t *testing.T) {
+	st := scanner.RepoStatus{
+		Branch: "main",
+		Porcelain: scanner.PorcelainStatus{Entries: []scanner.PorcelainEntry{
+			{Staging: 'M', Worktree: ' ', Path: "a"},
+			{Staging: 'A', Worktree: 'M', Path: "b"},
+			{Staging: ' ', Worktree: 'D', Path: "c"},
+			{Staging: '?', Worktree: '?', Path: "d"},
+		}},
+		FilteredBranches: []scanner.LocalBranchRef{{Name: "main", UnpushedCount: 2}},
+		Branches: []scanner.LocalBranchRef{{
+			Name: "main",
+			Locations: []scanner.BranchLocation{
+				{Name: "local", Exists: true, TipHash: "aaa"},
+				{Name: "origin", Exists: true, TipHash: "bbb", Incoming: 3},
+			},
+		}},
+		StashCount: 1,
+	}
+	for col, want := range map[string]string{
+		scanner.RepoColumnStatus: "+2 ~2 ?1",
+		scanner.RepoColumnBranch: "main",
+		scanner.RepoColumnSync:   "↑2↓3",
+		scanner.RepoColumnStash:  "$1",
+	} {
+		if got := repoColumnCell(col, st); got != want {
+			t.Errorf("%s = %q, want %q", col, got, want)
+		}
+	}
+	detached := scanner.RepoStatus{Branch: "abc1234def5678901234567890abcdef12345678", Detached: true}
+	if got := repoColumnCell(scanner.RepoColumnBranch, detached); got != "@abc1234d" {
+		t.Errorf("detached branch = %q", got)
+	}
+}
+
+func TestRepoListRowColumnsFitWidth(t *testing.T) {
+	m := newTestModel()
+	m.config = &scanner.Config{}
+	m.config.RepoList.Columns = []string{scanner.RepoColumnBranch, scanner.RepoColumnStash}
+	m.repositories.AddResult("/a", scanner.RepoStatus{Branch: "feature", StashCount: 2})
+	m.repositories.AddResult("/b", scanner.RepoStatus{Branch: "main"})
+	m.repoList = m.visibleRepoPaths()
+
+	cols := m.repoColumnLayout(80)
+	if len(cols) != 2 || cols[0].width != len("feature") || cols[1].width != 2 {
+		t.Fatalf("layout = %+v", cols)
+	}
+	row := m.repoListRow("/a", 80, cols, true)
+	if len(row) != 80 || !strings.HasSuffix(row, "feature  $2") {
+		t.Fatalf("row = %q", row)
+	}
+	if row := m.repoListRow("/b", 80, cols, true); !strings.HasSuffix(row, "main    ") {
+		t.Fatalf("row = %q", row)
+	}
+	// Too narrow: the rightmost column goes first, then the rest.
+	if cols := m.repoColumnLayout(40); len(cols) != 1 || cols[0].name != scanner.RepoColumnBranch {
+		t.Fatalf("narrow layout = %+v", cols)
+	}
+	if cols := m.repoColumnLayout(20); len(cols) != 0 {
+		t.Fatalf("narrowest layout = %+v", cols)
+	}
+}
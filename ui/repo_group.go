@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// repoGroupMode groups the repository list under collapsible headers (key g).
+type repoGroupMode int
+
+const (
+	// repoGroupNone is a flat list.
+	repoGroupNone repoGroupMode = iota
+	// repoGroupRoot groups by the scandirs.include root each repo was found under.
+	repoGroupRoot
+	// repoGroupParent groups by each repo's parent directory.
+	repoGroupParent
+	repoGroupModeCount
+)
+
+func (g repoGroupMode) String() string {
+	switch g {
+	case repoGroupRoot:
+		return "include root"
+	case repoGroupParent:
+		return "parent directory"
+	}
+	return "none"
+}
+
+// groupRowPrefix marks group header rows in repoList. It cannot start a path,
+// so header rows never collide with repositories.
+const groupRowPrefix = "\x00"
+
+// isGroupRow reports whether a repoList row is a group header.
+func isGroupRow(row string) bool {
+	return strings.HasPrefix(row, groupRowPrefix)
+}
+
+// groupRowKey returns the group a header row stands for.
+func groupRowKey(row string) string {
+	return strings.TrimPrefix(row, groupRowPrefix)
+}
+
+// repoGroupKey returns the group path belongs to in the current mode.
+func (m *model) repoGroupKey(path string) string {
+	if m.repoGroup == repoGroupRoot && m.config != nil {
+		best := ""
+		for _, root := range m.config.ScanDirs.Include {
+			root = filepath.Clean(root)
+			rel, err := filepath.Rel(root, path)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && len(root) > len(best) {
+				best = root
+			}
+		}
+		if best != "" {
+			return best
+		}
+	}
+	return filepath.Dir(path)
+}
+
+// groupRepoPaths turns sorted paths into rows with a header before each group.
+// Groups appear in the order of their first repository, so they follow the
+// sort mode; collapsed groups keep only their header. It records each group's
+// size for the header labels.
+func (m *model) groupRepoPaths(paths []string) []string {
+	if m.repoGroup == repoGroupNone {
+		return paths
+	}
+	var order []string
+	members := make(map[string][]string)
+	for _, p := range paths {
+		k := m.repoGroupKey(p)
+		if _, seen := members[k]; !seen {
+			order = append(order, k)
+		}
+		members[k] = append(members[k], p)
+	}
+	m.repoGroupSizes = make(map[string]int, len(order))
+	rows := make([]string, 0, len(paths)+len(order))
+	for _, k := range order {
+		m.repoGroupSizes[k] = len(members[k])
+		rows = append(rows, groupRowPrefix+k)
+		if !m.collapsedGroups[k] {
+			rows = append(rows, members[k]...)
+		}
+	}
+	return rows
+}
+
+// groupHeaderLabel renders a header row: expand marker, group path, and size.
+func (m *model) groupHeaderLabel(row string) string {
+	k := groupRowKey(row)
+	marker := "▾"
+	if m.collapsedGroups[k] {
+		marker = "▸"
+	}
+	return styleBold.Render(fmt.Sprintf("%s %s (%d)", marker, k, m.repoGroupSizes[k]))
+}
+
+// rebuildRepoList recomputes the rows, keeping the selected repository (or
+// header) selected. A repository hidden in a collapsed group selects its header.
+func (m *model) rebuildRepoList() {
+	selected := ""
+	if m.cursor >= 0 && m.cursor < len(m.repoList) {
+		selected = m.repoList[m.cursor]
+	}
+	m.repoList = m.visibleRepoPaths()
+	i := slices.Index(m.repoList, selected)
+	if i < 0 && selected != "" && !isGroupRow(selected) && m.repoGroup != repoGroupNone {
+		i = slices.Index(m.repoList, groupRowPrefix+m.repoGroupKey(selected))
+	}
+	if i >= 0 {
+		m.cursor = i
+		return
+	}
+	m.cursor = min(m.cursor, max(0, len(m.repoList)-1))
+	m.statusFileSelected = false
+	m.diffNeedsRefresh = true
+}
+
+// cycleRepoGroup switches to the next grouping mode.
+func (m *model) cycleRepoGroup() {
+	m.repoGroup = (m.repoGroup + 1) % repoGroupModeCount
+	m.rebuildRepoList()
+	log.Printf("grouping repositories by %s", m.repoGroup)
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+}
+
+// toggleSelectedGroup collapses or expands the group under the cursor; it
+// reports false when the cursor is not on a header.
+func (m *model) toggleSelectedGroup() bool {
+	if m.cursor < 0 || m.cursor >= len(m.repoList) || !isGroupRow(m.repoList[m.cursor]) {
+		return false
+	}
+	k := groupRowKey(m.repoList[m.cursor])
+	if m.collapsedGroups == nil {
+		m.collapsedGroups = make(map[string]bool)
+	}
+	m.collapsedGroups[k] = !m.collapsedGroups[k]
+	m.rebuildRepoList()
+	m.syncViewports()
+	return true
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestRepoGroupsCollapseAndKeepSelection(t *testing.T) {
+	m := newTestModel()
+	m.width = 100
+	m.config = &scanner.Config{}
+	m.config.ScanDirs.Include = []string{"/src", "/work"}
+	for _, p := range []string{"/src/x/a", "/src/y/b", "/work/c"} {
+		m.repositories.AddResult(p, scanner.RepoStatus{Branch: "main"})
+	}
+	m.repoList = m.visibleRepoPaths()
+	m.cursor = 1 // /src/y/b
+
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}); !handled {
+		t.Fatal("g should be handled")
+	}
+	want := []string{groupRowPrefix + "/src", "/src/x/a", "/src/y/b", groupRowPrefix + "/work", "/work/c"}
+	if strings.Join(m.repoList, ",") != strings.Join(want, ",") || m.currentRepo() != "/src/y/b" {
+		t.Fatalf("root groups: list=%q selected=%q", m.repoList, m.currentRepo())
+	}
+	if got := m.repoListRow(m.repoList[0], 80, nil, true); !strings.Contains(got, "▾ /src (2)") {
+		t.Fatalf("header = %q", got)
+	}
+
+	m.cycleRepoGroup()
+	if m.repoGroup != repoGroupParent || len(m.repoList) != 6 || m.currentRepo() != "/src/y/b" {
+		t.Fatalf("parent groups: list=%q selected=%q", m.repoList, m.currentRepo())
+	}
+
+	// Collapse /src/y from its member: the header takes over the selection.
+	m.collapsedGroups = map[string]bool{"/src/y": true}
+	m.rebuildRepoList()
+	if !isGroupRow(m.repoList[m.cursor]) || groupRowKey(m.repoList[m.cursor]) != "/src/y" || m.currentRepo() != "" {
+		t.Fatalf("collapsed: list=%q cursor=%d", m.repoList, m.cursor)
+	}
+	if m.repoPaneReady() {
+		t.Fatal("repo commands should be unavailable on a header row")
+	}
+
+	// Rescan keeps the collapsed state; Space expands it again.
+	m.finishScan(scanResult{mgs: m.repositories})
+	if !m.collapsedGroups["/src/y"] || groupRowKey(m.repoList[m.cursor]) != "/src/y" {
+		t.Fatalf("after rescan: list=%q cursor=%d", m.repoList, m.cursor)
+	}
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}); !handled {
+		t.Fatal("Space on a header should be handled")
+	}
+	if m.collapsedGroups["/src/y"] || len(m.repoList) != 6 {
+		t.Fatalf("expanded: list=%q", m.repoList)
+	}
+}
+
+func TestFinishScanKeepsSelection(t *testing.T) {
+	m := newTestModel()
+	for _, p := range []string{"/a", "/b", "/c"} {
+		m.repositories.AddResult(p, scanner.RepoStatus{Branch: "main"})
+	}
+	m.repoList = m.visibleRepoPaths()
+	m.cursor = 2
+
+	mgs := scanner.NewMultiGitStatus()
+	for _, p := range []string{"/0", "/a", "/c"} {
+		mgs.AddResult(p, scanner.RepoStatus{Branch: "main"})
+	}
+	m.finishScan(scanResult{mgs: mgs})
+	if m.currentRepo() != "/c" {
+		t.Fatalf("selected %q after rescan, want /c", m.currentRepo())
+	}
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// repoSortMode orders the repository list (o / O cycle through the modes).
+type repoSortMode int
+
+const (
+	// repoSortPath is alphabetical by path.
+	repoSortPath repoSortMode = iota
+	// repoSortLastCommit puts the newest local branch tip first.
+	repoSortLastCommit
+	// repoSortChangedFiles puts the most uncommitted paths first.
+	repoSortChangedFiles
+	// repoSortUnpushedCount puts the most unpushed commits first.
+	repoSortUnpushedCount
+	// repoSortLastModified puts the most recently modified working trees first.
+	repoSortLastModified
+	// repoSortDirtySince puts the longest-dirty working trees first.
+	repoSortDirtySince
+	// repoSortUnpushed puts the oldest unpushed commits first.
+	repoSortUnpushed
+	// repoSortScore puts the highest risk score first.
+	repoSortScore
+	repoSortModeCount
+)
+
+func (s repoSortMode) String() string {
+	switch s {
+	case repoSortLastCommit:
+		return "last commit"
+	case repoSortChangedFiles:
+		return "changed files"
+	case repoSortUnpushedCount:
+		return "unpushed commits"
+	case repoSortLastModified:
+		return "last modified"
+	case repoSortDirtySince:
+		return "dirty since"
+	case repoSortUnpushed:
+		return "oldest unpushed"
+	case repoSortScore:
+		return "risk score"
+	}
+	return "path"
+}
+
+// sortRepoPaths reorders paths (already alphabetical) for the current sort
+// mode. Repos without the sorted-by value keep alphabetical order after the rest.
+func (m *model) sortRepoPaths(paths []string) {
+	// key returns the ascending sort key, or false when the repo has no value.
+	var key func(st scanner.RepoStatus) (float64, bool)
+	switch m.repoSort {
+	case repoSortLastCommit:
+		key = func(st scanner.RepoStatus) (float64, bool) {
+			t := st.NewestCommitUnix()
+			return -float64(t), t > 0
+		}
+	case repoSortChangedFiles:
+		key = func(st scanner.RepoStatus) (float64, bool) {
+			n := len(st.Porcelain.Entries)
+			return -float64(n), n > 0
+		}
+	case repoSortUnpushedCount:
+		key = func(st scanner.RepoStatus) (float64, bool) {
+			n := st.UnpushedCommitCount()
+			return -float64(n), n > 0
+		}
+	case repoSortLastModified:
+		key = func(st scanner.RepoStatus) (float64, bool) {
+			return -float64(st.LastModifiedUnix), st.LastModifiedUnix > 0
+		}
+	case repoSortDirtySince:
+		key = func(st scanner.RepoStatus) (float64, bool) {
+			return float64(st.DirtySinceUnix), st.DirtySinceUnix > 0
+		}
+	case repoSortUnpushed:
+		key = func(st scanner.RepoStatus) (float64, bool) {
+			t := st.OldestUnpushedUnix()
+			return float64(t), t > 0
+		}
+	case repoSortScore:
+		key = func(st scanner.RepoStatus) (float64, bool) {
+			return -st.Score.Total, st.Score.Total > 0
+		}
+	default:
+		return
+	}
+	type sortKey struct {
+		v  float64
+		ok bool
+	}
+	keys := make(map[string]sortKey, len(paths))
+	for _, p := range paths {
+		if st, ok := m.repositories.Get(p); ok {
+			v, ok := key(st)
+			keys[p] = sortKey{v, ok}
+		}
+	}
+	slices.SortStableFunc(paths, func(a, b string) int {
+		ka, kb := keys[a], keys[b]
+		switch {
+		case ka.ok != kb.ok:
+			if ka.ok {
+				return -1
+			}
+			return 1
+		case !ka.ok:
+			return 0
+		}
+		return cmp.Compare(ka.v, kb.v)
+	})
+}
+
+// cycleRepoSort switches to the next (or, with back, previous) sort mode,
+// keeping the selected repository selected.
+func (m *model) cycleRepoSort(back bool) {
+	step := repoSortMode(1)
+	if back {
+		step = repoSortModeCount - 1
+	}
+	m.repoSort = (m.repoSort + step) % repoSortModeCount
+	m.rebuildRepoList()
+	log.Printf("sorting repositories by %s", m.repoSort)
+	m.syncViewports()
+}
+
+// repoPaneTitle names the repository pane and its current sort and grouping.
+func (m *model) repoPaneTitle() string {
+	title := fmt.Sprintf("Repositories (sort: %s)", m.repoSort)
+	if m.repoGroup != repoGroupNone {
+		title = fmt.Sprintf("Repositories (sort: %s, group: %s)", m.repoSort, m.repoGroup)
+	}
+	return m.withFilterTitle(paneRepo, title)
+}
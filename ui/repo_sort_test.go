@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestCycleRepoSortKeepsSelection(t *testing.T) {
+	m := newTestModel()
+	m.width = 100
+	dirty := scanner.PorcelainStatus{Entries: []scanner.PorcelainEntry{{Path: "f"}}}
+	m.repositories.AddResult("/a", scanner.RepoStatus{Porcelain: dirty, DirtySinceUnix: 300, LastModifiedUnix: 400})
+	m.repositories.AddResult("/b", scanner.RepoStatus{
+		FilteredBranches: []scanner.LocalBranchRef{{Name: "x", UnpushedCount: 2, OldestUnpushedUnix: 50}},
+		Branches:         []scanner.LocalBranchRef{{Name: "x", TipUnix: 900}},
+	})
+	m.repositories.AddResult("/c", scanner.RepoStatus{
+		Porcelain:        scanner.PorcelainStatus{Entries: []scanner.PorcelainEntry{{Path: "f"}, {Path: "g"}}},
+		DirtySinceUnix:   100,
+		LastModifiedUnix: 500,
+		Score:            scanner.RiskScore{Total: 7},
+	})
+	m.repoList = m.visibleRepoPaths()
+	m.cursor = 0
+
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}}); !handled {
+		t.Fatal("o should be handled")
+	}
+	if m.repoSort != repoSortLastCommit || m.currentRepo() != "/a" {
+		t.Fatalf("after o: mode=%v selected=%q", m.repoSort, m.currentRepo())
+	}
+	for mode, want := range map[repoSortMode]string{
+		repoSortLastCommit:    "/b,/a,/c",
+		repoSortChangedFiles:  "/c,/a,/b",
+		repoSortUnpushedCount: "/b,/a,/c",
+		repoSortLastModified:  "/c,/a,/b",
+		repoSortDirtySince:    "/c,/a,/b",
+		repoSortUnpushed:      "/b,/a,/c",
+		repoSortScore:         "/c,/a,/b",
+		repoSortPath:          "/a,/b,/c",
+	} {
+		m.repoSort = mode
+		m.rebuildRepoList()
+		if got := strings.Join(m.repoList, ","); got != want {
+			t.Errorf("%v: list=%v, want %v", mode, got, want)
+		}
+		if m.currentRepo() != "/a" {
+			t.Errorf("%v: selection moved to %q", mode, m.currentRepo())
+		}
+	}
+	m.repoSort = repoSortPath
+	m.cycleRepoSort(true)
+	if m.repoSort != repoSortScore || !strings.Contains(m.repoPaneTitle(), "sort: risk score") {
+		t.Fatalf("O from path: mode=%v title=%q", m.repoSort, m.repoPaneTitle())
+	}
+}
@@ -0,0 +1,333 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// newStashTable builds the Stash pane table.
+func newStashTable() table.Model {
+	return table.New(
+		table.WithColumns(stashColumns(48)),
+		table.WithRows(nil),
+		table.WithFocused(false),
+		table.WithHeight(layoutDefaultTableViewRows),
+	)
+}
+
+// stashColumns sizes the Stash pane columns: entry, age, then the message.
+func stashColumns(totalWidth int) []table.Column {
+	const cols = 3
+	refW, ageW := 10, 8
+	msgW := max(1, totalWidth-2*cols-refW-ageW)
+	return []table.Column{
+		{Title: "Stash", Width: refW},
+		{Title: "Age", Width: ageW},
+		{Title: "Message", Width: msgW},
+	}
+}
+
+// lowerLeftPane is the pane shown below Status: Branches, or Stash after $.
+func (m *model) lowerLeftPane() pane {
+	if m.stashShown {
+		return paneStash
+	}
+	return paneBranches
+}
+
+// lowerLeftView renders the table of the pane shown below Status.
+func (m *model) lowerLeftView() string {
+	if m.stashShown {
+		return m.stashTable.View()
+	}
+	return m.branchTable.View()
+}
+
+// lowerLeftTitle is the border title of the pane shown below Status.
+func (m *model) lowerLeftTitle() string {
+	if !m.stashShown {
+		return m.withFilterTitle(paneBranches, "Branches")
+	}
+	if n := len(m.stashEntries); n > 0 {
+		return fmt.Sprintf("Stash (%d)", n)
+	}
+	return "Stash"
+}
+
+// toggleStashPane swaps Branches and Stash below Status and focuses the one shown.
+func (m *model) toggleStashPane() bool {
+	if m.err != nil {
+		return false
+	}
+	m.stashShown = m.focus != paneStash
+	m.focus = m.lowerLeftPane()
+	if m.zoomed {
+		m.zoomTarget = m.focus
+	}
+	m.syncViewports()
+	return true
+}
+
+// refreshStashContent lists the selected repository's stash entries. They are
+// loaded once per stash count and newest entry, which the scan records.
+func (m *model) refreshStashContent(totalWidth int) {
+	m.stashTable.SetColumns(stashColumns(totalWidth))
+	m.stashEntries = nil
+	if !m.stashShown {
+		return
+	}
+	repo := m.currentRepo()
+	st, ok := m.repositories.Get(repo)
+	if !ok {
+		m.stashTable.SetRows([]table.Row{{"-", "-", "(select repository)"}})
+		return
+	}
+	key := fmt.Sprintf("%s\x00%d\x00%d", repo, st.StashCount, st.NewestStashUnix)
+	entries, cached := m.stashCache[key]
+	if !cached {
+		var err error
+		if entries, err = scanner.Stashes(repo); err != nil {
+			log.Printf("git: %v", err)
+		}
+		if m.stashCache == nil {
+			m.stashCache = make(map[string][]scanner.StashEntry)
+		}
+		m.stashCache[key] = entries
+	}
+	m.stashEntries = entries
+	if len(entries) == 0 {
+		m.stashTable.SetRows([]table.Row{{"-", "-", "(no stash entries)"}})
+		return
+	}
+	rows := make([]table.Row, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, table.Row{e.Ref, relativeTime(e.Unix), e.Message})
+	}
+	m.stashTable.SetRows(rows)
+}
+
+// selectedStash returns the stash entry under the Stash pane cursor.
+func (m *model) selectedStash() (scanner.StashEntry, bool) {
+	i := m.stashTable.Cursor()
+	if i < 0 || i >= len(m.stashEntries) {
+		return scanner.StashEntry{}, false
+	}
+	return m.stashEntries[i], true
+}
+
+// syncStashDiff points the Diff pane at the selected stash entry while the
+// Stash pane has focus, and keeps it as syncBranchDiff does.
+func (m *model) syncStashDiff() {
+	if m.keepDiffDetail() {
+		return
+	}
+	hash := ""
+	if e, ok := m.selectedStash(); ok && m.focus == paneStash {
+		hash = e.Hash
+		m.diffDetailRepo = m.currentRepo()
+	}
+	if hash != m.diffStash {
+		m.diffStash = hash
+		m.diffNeedsRefresh = true
+	}
+}
+
+// showStashDiff loads git stash show for diffStash, untracked files included.
+func (m *model) showStashDiff(repo string) {
+	m.diffTarget = repo + "\x00" + m.diffStash
+	m.diffHunkSelected = false
+	out, err := runGitPatch(repo, "stash show", "--include-untracked", "--stat", "--patch", m.diffStash)
+	if err != nil {
+		m.diffErr = err
+		m.diffContent = fmt.Sprintf("git stash show failed: %v", err)
+		return
+	}
+	m.diffRaw = out
+	m.renderDiffContent()
+}
+
+// diffStashRef is the stash@{n} name of the entry the Diff pane shows.
+func (m *model) diffStashRef() string {
+	for _, e := range m.stashEntries {
+		if e.Hash == m.diffStash {
+			return e.Ref
+		}
+	}
+	return shortHash(m.diffStash)
+}
+
+// handleStashKey runs the Stash pane keys: n new stash, a apply, p pop, d drop.
+func (m *model) handleStashKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.focus != paneStash || m.err != nil || m.currentRepo() == "" {
+		return nil, false
+	}
+	if msg.String() == "n" {
+		m.stashCreateOpen = true
+		m.stashUntracked = false
+		m.stashInput = textinput.New()
+		m.stashInput.Prompt = "Message: "
+		m.stashInput.Placeholder = "optional"
+		m.stashInput.CharLimit = 200
+		return m.stashInput.Focus(), true
+	}
+	e, ok := m.selectedStash()
+	if !ok {
+		return nil, false
+	}
+	switch msg.String() {
+	case "a":
+		if m.stashRefCurrent(e) {
+			m.runStashGit("stash", "apply", e.Ref)
+		}
+	case "p":
+		if m.stashRefCurrent(e) {
+			m.runStashGit("stash", "pop", e.Ref)
+		}
+	case "d":
+		if !m.stashRefCurrent(e) {
+			return nil, true
+		}
+		m.stashDropConfirmOpen = true
+		m.stashDropPending = e
+		m.deleteConfirmYes = false
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// handleStashCreateKey processes keys while the new-stash overlay is open. Tab
+// toggles untracked files; other keys edit the message.
+func (m *model) handleStashCreateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.stashCreateOpen = false
+		return m, nil
+	case "tab":
+		m.stashUntracked = !m.stashUntracked
+		return m, nil
+	case "enter":
+		args := []string{"stash", "push"}
+		if m.stashUntracked {
+			args = append(args, "--include-untracked")
+		}
+		if msg := strings.TrimSpace(m.stashInput.Value()); msg != "" {
+			args = append(args, "-m", msg)
+		}
+		if m.runStashGit(args...) {
+			m.stashCreateOpen = false
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.stashInput, cmd = m.stashInput.Update(msg)
+	return m, cmd
+}
+
+// handleStashDropConfirmKey processes keys while the drop confirmation is open.
+func (m *model) handleStashDropConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		m.stashDropConfirmOpen = false
+	case "enter":
+		m.stashDropConfirmOpen = false
+		if m.deleteConfirmYes && m.stashRefCurrent(m.stashDropPending) {
+			m.runStashGit("stash", "drop", m.stashDropPending.Ref)
+		}
+	default:
+		m.handleConfirmNavKey(msg)
+	}
+	return m, nil
+}
+
+// stashRefCurrent reports whether e.Ref still names the entry listed. The list
+// is loaded per scan, so a stash pushed or dropped outside dirtygit since then
+// shifts the stash@{n} names; on a mismatch the list is reloaded and nothing runs.
+func (m *model) stashRefCurrent(e scanner.StashEntry) bool {
+	repo := m.currentRepo()
+	hash, err := runGitInRepo(repo, "rev-parse", "--verify", "--quiet", e.Ref)
+	if err == nil && hash == e.Hash {
+		return true
+	}
+	log.Printf("stash: %s is no longer %s; reloaded the stash list", e.Ref, shortHash(e.Hash))
+	m.stashCache = nil
+	m.refreshRepoStatus(repo)
+	m.syncViewports()
+	return false
+}
+
+// runStashGit runs a stash command in the selected repository, logs it and its
+// output, and refreshes that repository. The refresh runs on failure too: a
+// conflicting apply or pop still leaves changes in the worktree.
+func (m *model) runStashGit(args ...string) bool {
+	repo := m.currentRepo()
+	log.Printf("git %s", strings.Join(args, " "))
+	out, err := runGitInRepo(repo, args...)
+	if err != nil {
+		log.Printf("git: %v", err)
+	} else {
+		for line := range strings.SplitSeq(out, "\n") {
+			if line != "" {
+				log.Printf("git: %s", line)
+			}
+		}
+	}
+	// Two stashes made within a second look alike to the cache key.
+	m.stashCache = nil
+	m.refreshRepoStatus(repo)
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+	return err == nil
+}
+
+// renderStashCreateOverlay shows the message input and the untracked toggle.
+func (m *model) renderStashCreateOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+	m.stashInput.Width = max(1, innerW-len(m.stashInput.Prompt)-1)
+	untracked := "[ ] Include untracked files"
+	if m.stashUntracked {
+		untracked = "[x] Include untracked files"
+	}
+	parts := []string{
+		styleBold.Render("Stash changes"), "",
+		styleDim.Render(truncateASCII(m.currentRepo(), innerW)), "",
+		m.stashInput.View(), "",
+		untracked, "",
+		styleDim.Render("Enter stash · Tab untracked · Esc cancel"),
+	}
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(strings.Join(parts, "\n")))
+}
+
+// renderStashDropConfirmOverlay asks before git stash drop.
+func (m *model) renderStashDropConfirmOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+	e := m.stashDropPending
+	parts := []string{
+		styleBold.Render("Drop this stash entry?"), "",
+		styleDim.Render(truncateASCII(m.currentRepo(), innerW)),
+		truncateASCII(e.Ref+"  "+e.Message, innerW), "",
+		warnBlock(innerW).Render("git stash drop deletes the entry. Its changes are lost unless they are applied somewhere else."), "",
+		deleteConfirmButtons(m.deleteConfirmYes), "",
+		deleteConfirmFooter(),
+	}
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(strings.Join(parts, "\n")))
+}
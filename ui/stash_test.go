@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestStashPaneCreatePopDrop(t *testing.T) {
+	repo := lineFixtureRepo(t, lineFixtureBase, lineFixtureWork)
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("untracked\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitT(t, repo, "config", "user.email", "u@x")
+	runGitT(t, repo, "config", "user.name", "u")
+	runGitT(t, repo, "config", "color.ui", "always")
+	runGitT(t, repo, "config", "diff.noprefix", "true")
+	// Keep the repository listed once its changes are stashed away.
+	cfg := &scanner.Config{Rules: []scanner.PolicyRule{{Name: "stashes", When: scanner.ReasonStash, Action: "include"}}}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.syncViewports()
+	prevLog := log.Writer()
+	log.SetOutput(m.logBuf)
+	defer log.SetOutput(prevLog)
+
+	key := func(s string) {
+		t.Helper()
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+	key("$")
+	if m.focus != paneStash || !strings.Contains(m.View(), "(no stash") {
+		t.Fatalf("$ should focus an empty Stash pane; focus %v", m.focus)
+	}
+
+	key("n")
+	key("wip")
+	m.handleKey(tea.KeyMsg{Type: tea.KeyTab})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.stashCreateOpen {
+		t.Fatalf("the overlay should close after stashing; log:\n%s", m.logBuf.String())
+	}
+	if len(m.stashEntries) != 1 || !strings.HasSuffix(m.stashEntries[0].Message, ": wip") {
+		t.Fatalf("entries = %+v", m.stashEntries)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "new.txt")); !os.IsNotExist(err) {
+		t.Fatal("the untracked file should be stashed too")
+	}
+	if !strings.Contains(m.diffRaw, "+++ b/new.txt") || !strings.Contains(m.diffRaw, "+\tfmt.Println(\"debug\")") ||
+		strings.Contains(m.diffRaw, "\x1b[") ||
+		!strings.Contains(m.diffPaneBorderTitle(), "stash@{0}") {
+		t.Fatalf("diff:\n%s\ntitle %q", m.diffRaw, m.diffPaneBorderTitle())
+	}
+	m.focus = paneDiff
+	m.syncViewports()
+	if !strings.Contains(m.diffRaw, "new.txt") {
+		t.Fatal("Diff focus should keep the stash entry")
+	}
+	m.focus = paneStash
+	m.syncViewports()
+
+	key("p")
+	if got, _ := os.ReadFile(filepath.Join(repo, "m.go")); string(got) != lineFixtureWork || len(m.stashEntries) != 0 {
+		t.Fatalf("pop: m.go = %q, entries %+v", got, m.stashEntries)
+	}
+
+	key("n")
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	key("d")
+	if !m.stashDropConfirmOpen || !strings.Contains(m.View(), "Drop this stash entry?") {
+		t.Fatal("d should ask before dropping")
+	}
+	key("y")
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if out := runGitT(t, repo, "stash", "list"); out != "" {
+		t.Fatalf("stash list after drop = %q", out)
+	}
+	if !strings.Contains(m.logBuf.String(), "git stash drop stash@{0}") {
+		t.Fatalf("log:\n%s", m.logBuf.String())
+	}
+
+	// Tab treats Stash as the pane below Status; $ brings Branches back.
+	m.handleKey(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyTab})
+	if m.focus != paneStash {
+		t.Fatalf("Shift+Tab then Tab: focus %v, want Stash", m.focus)
+	}
+	key("$")
+	if m.focus != paneBranches || m.stashShown {
+		t.Fatalf("$ from Stash: focus %v, stashShown %v", m.focus, m.stashShown)
+	}
+}
+
+func TestStashActionsRefuseShiftedRef(t *testing.T) {
+	repo := lineFixtureRepo(t, lineFixtureBase, lineFixtureWork)
+	runGitT(t, repo, "config", "user.email", "u@x")
+	runGitT(t, repo, "config", "user.name", "u")
+	runGitT(t, repo, "stash", "push", "-q", "-m", "first")
+	cfg := &scanner.Config{Rules: []scanner.PolicyRule{{Name: "stashes", When: scanner.ReasonStash, Action: "include"}}}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.syncViewports()
+	prevLog := log.Writer()
+	log.SetOutput(m.logBuf)
+	defer log.SetOutput(prevLog)
+	key := func(s string) {
+		t.Helper()
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+	key("$")
+	if len(m.stashEntries) != 1 {
+		t.Fatalf("entries = %+v", m.stashEntries)
+	}
+
+	// A stash pushed outside dirtygit makes the listed stash@{0} a different entry.
+	if err := os.WriteFile(filepath.Join(repo, "m.go"), []byte("package m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitT(t, repo, "stash", "push", "-q", "-m", "second")
+	key("p")
+	if got := runGitT(t, repo, "stash", "list"); strings.Count(got, "\n") != 2 {
+		t.Fatalf("p should refuse the shifted entry; stash list:\n%s", got)
+	}
+	if !strings.Contains(m.logBuf.String(), "stash: stash@{0} is no longer") || len(m.stashEntries) != 2 {
+		t.Fatalf("the list should reload; entries %+v log:\n%s", m.stashEntries, m.logBuf.String())
+	}
+}
@@ -29,7 +29,7 @@ func (m *model) autoLayoutBodies() paneLayout {
 			return paneLayout{repo: body}
 		case paneStatus:
 			return paneLayout{status: body}
-		case paneBranches:
+		case paneBranches, paneStash:
 			return paneLayout{branch: body}
 		case paneDiff:
 			return paneLayout{diff: body}
@@ -124,7 +124,7 @@ func (m *model) paneAtTerminalCell(x, y int) (pane, bool) {
 			if y < statusOuter {
 				return paneStatus, true
 			}
-			return paneBranches, true
+			return m.lowerLeftPane(), true
 		}
 		return paneDiff, true
 	}
@@ -211,6 +211,10 @@ func (m *model) applyViewportAndPanes(syncDiff bool) {
 	m.refreshStatusContent()
 	m.refreshBranchContent(branchInnerW)
 	m.branchTable.SetHeight(lay.branch)
+	m.refreshStashContent(branchInnerW)
+	m.stashTable.SetHeight(lay.branch)
+	m.syncBranchDiff()
+	m.syncStashDiff()
 	if syncDiff {
 		m.refreshDiffContent()
 	} else if m.diffNeedsRefresh {
@@ -354,6 +358,27 @@ func branchRemoteSummaryFromLocations(locations []scanner.BranchLocation) string
 	return strings.Join(parts, ", ")
 }
 
+// branchRemoteSummary is branchRemoteSummaryFromLocations prefixed with the
+// upstream-gone state, so pruned branches read differently from new local work.
+func branchRemoteSummary(lb scanner.LocalBranchRef) string {
+	summary := branchRemoteSummaryFromLocations(lb.Locations)
+	var gone string
+	switch {
+	case lb.IsGoneMerged():
+		gone = "gone, merged into " + lb.MergedInto
+	case lb.IsGoneUnmerged():
+		gone = fmt.Sprintf("gone, %d unmerged", lb.UnmergedCount)
+	case lb.UpstreamGone:
+		gone = "gone"
+	default:
+		return summary
+	}
+	if summary == "-" {
+		return gone
+	}
+	return gone + "; " + summary
+}
+
 // sortLocalBranchesByTipNewestFirst orders branches for the table: latest tip commit first.
 // Tie-breaker is name so order is stable when tips share a timestamp.
 func sortLocalBranchesByTipNewestFirst(branches []scanner.LocalBranchRef) {
@@ -374,6 +399,8 @@ func (m *model) refreshBranchContent(totalWidth int) {
 
 	repo := m.currentRepo()
 	st, ok := m.repositories.Get(repo)
+	m.branchRows = nil
+	m.branchRowDetails = nil
 	if !ok {
 		m.branchTable.SetRows([]table.Row{{"(select repository)", "-", "-", "-"}})
 		m.branchTable.SetHeight(layoutMinBodyLines)
@@ -387,14 +414,30 @@ func (m *model) refreshBranchContent(totalWidth int) {
 	// show only the dirty branches in the UI
 	rows := make([]table.Row, 0, len(locals))
 	for _, lb := range locals {
-		remote := branchRemoteSummaryFromLocations(lb.Locations)
+		if !m.branchMatches(lb) {
+			continue
+		}
+		remote := branchRemoteSummary(lb)
 
+		m.branchRows = append(m.branchRows, lb)
+		m.branchRowDetails = append(m.branchRowDetails, branchRowDetail{})
 		rows = append(rows, table.Row{
 			lb.DisplayName(),
 			shortHash(lb.TipHash),
 			relativeTime(lb.TipUnix),
 			remote,
 		})
+		rows = m.appendBranchDetailRows(rows, repo, lb)
+	}
+	if n := len(st.LostCommits); n > 0 && m.filterQuery(paneBranches) == "" {
+		// LostCommits is newest reflog entry first.
+		newest := st.LostCommits[0]
+		rows = append(rows, table.Row{
+			"(lost commits)",
+			shortHash(newest.Hash),
+			relativeTime(newest.ReflogUnix),
+			fmt.Sprintf("%d unreachable (L)", n),
+		})
 	}
 
 	m.branchTable.SetRows(rows)
@@ -424,41 +467,57 @@ func relativeTime(unix int64) string {
 	return fmt.Sprintf("%dd", int(d.Hours()/24))
 }
 
-// refreshStatusContent rebuilds status rows for the selected repository.
+// refreshStatusContent rebuilds status rows for the selected repository, as a
+// flat list or, in tree mode, grouped under directory rows.
 func (m *model) refreshStatusContent() {
 	repo := m.currentRepo()
 	st, ok := m.repositories.Get(repo)
-	rows := make([]table.Row, 0)
-	paths := make([]string, 0)
+	out := statusRows{rows: make([]table.Row, 0), paths: make([]string, 0)}
 	if ok && len(st.Porcelain.Entries) > 0 {
-		entries := append([]scanner.PorcelainEntry(nil), st.Porcelain.Entries...)
+		entries := make([]scanner.PorcelainEntry, 0, len(st.Porcelain.Entries))
+		for _, entry := range st.Porcelain.Entries {
+			if m.statusEntryMatches(entry) {
+				entries = append(entries, entry)
+			}
+		}
 		sort.Slice(entries, func(i, j int) bool {
 			return entries[i].Path < entries[j].Path
 		})
-		for _, entry := range entries {
-			path := entry.Path
-			if entry.OriginalPath != "" {
-				path = fmt.Sprintf("%s -> %s", entry.OriginalPath, entry.Path)
+		if m.statusTree {
+			m.appendStatusTreeRows(&out, repo, buildStatusTree(entries), 0)
+		} else {
+			for _, entry := range entries {
+				out.add(statusEntryRow(entry, entry.Path), entry.Path, false)
 			}
-			rows = append(rows, table.Row{
-				statusCodeLabel(entry.Worktree),
-				statusCodeLabel(entry.Staging),
-				path,
-			})
-			paths = append(paths, entry.Path)
 		}
 	}
-	m.statusPaths = paths
-	if len(paths) == 0 {
+	m.statusPaths = out.paths
+	m.statusDirRows = out.dirs
+	m.statusDirEntries = out.dirEntries
+	if len(out.paths) == 0 {
 		m.statusFileSelected = false
 	}
-	m.statusTable.SetRows(rows)
-	if len(rows) > 0 && m.statusTable.Cursor() >= len(rows) {
-		m.statusTable.SetCursor(len(rows) - 1)
+	m.statusTable.SetRows(out.rows)
+	if len(out.rows) > 0 && m.statusTable.Cursor() >= len(out.rows) {
+		m.statusTable.SetCursor(len(out.rows) - 1)
 	}
 	m.applyStatusTableFocusAndStyles()
 }
 
+// statusPaneTitle is the Status pane title, followed by how many entries each
+// ignore source hid for the selected repository, truncated to maxW.
+func (m *model) statusPaneTitle(maxW int) string {
+	st, ok := m.repositories.Get(m.currentRepo())
+	if !ok || len(st.Hidden) == 0 {
+		return "Status"
+	}
+	parts := make([]string, 0, len(st.Hidden))
+	for _, h := range st.Hidden {
+		parts = append(parts, fmt.Sprintf("%d %s", h.Count, h.Source))
+	}
+	return truncateASCII("Status (hidden: "+strings.Join(parts, ", ")+")", max(len("Status"), maxW))
+}
+
 // selectedStatusPath returns the currently highlighted file path.
 func (m *model) selectedStatusPath() string {
 	if !m.statusFileSelected {
@@ -516,23 +575,26 @@ func statusCodeLabel(code git.StatusCode) string {
 	}
 }
 
-// currentRepo returns the repository currently selected in the list.
+// currentRepo returns the repository currently selected in the list, or ""
+// when a group header (or nothing) is selected.
 func (m *model) currentRepo() string {
-	if m.cursor < 0 || m.cursor >= len(m.repoList) {
+	if m.cursor < 0 || m.cursor >= len(m.repoList) || isGroupRow(m.repoList[m.cursor]) {
 		return ""
 	}
 	return m.repoList[m.cursor]
 }
 
-// repoPaneReady is true when the Repo pane is focused and the list is usable.
+// repoPaneReady is true when the Repo pane is focused with a repository selected.
 func (m *model) repoPaneReady() bool {
-	return m.focus == paneRepo && m.err == nil && len(m.repoList) > 0
+	return m.focus == paneRepo && m.err == nil && m.currentRepo() != ""
 }
 
 // interactiveAppReady is true when the main TUI (not a modal) is on screen and idle.
 func (m *model) interactiveAppReady() bool {
-	return !m.helpOpen && !m.deleteRepoConfirmOpen && !m.deleteStatusFileConfirmOpen &&
-		!m.checkoutStatusFileConfirmOpen && !m.scanning && m.err == nil
+	return !m.helpOpen && !m.whyOpen && !m.deleteRepoConfirmOpen && !m.deleteStatusFileConfirmOpen &&
+		!m.checkoutStatusFileConfirmOpen && !m.discardHunkConfirmOpen && !m.lostCommitsOpen &&
+		!m.mergedBranchesOpen && !m.commitOpen && !m.pushOpen && !m.branchMenuOpen &&
+		!m.stashCreateOpen && !m.stashDropConfirmOpen && !m.scanning && m.err == nil
 }
 
 // mouseFocusClickReady is true when left-click to change pane focus is allowed.
@@ -22,6 +22,7 @@ func newTestModel() *model {
 		focus:            paneRepo,
 		statusTable:      newStatusTable(),
 		branchTable:      newBranchTable(),
+		stashTable:       newStashTable(),
 		diffMode:         diffModeWorktree,
 		diffNeedsRefresh: true,
 	}
@@ -503,3 +504,25 @@ branches:
 		}
 	}
 }
+
+func TestStatusPaneTitleShowsHiddenCounts(t *testing.T) {
+	m := newTestModel()
+	m.repoList = []string{"/repo"}
+	m.repositories.AddResult("/repo", scanner.RepoStatus{Branch: "main"})
+	if got := m.statusPaneTitle(80); got != "Status" {
+		t.Fatalf("title = %q, want Status", got)
+	}
+	m.repositories.AddResult("/repo", scanner.RepoStatus{
+		Branch: "main",
+		Hidden: []scanner.IgnoreSourceCount{
+			{Source: scanner.IgnoreSourceConfig, Count: 3},
+			{Source: scanner.IgnoreSourceRepoFile, Count: 1},
+		},
+	})
+	if got := m.statusPaneTitle(80); got != "Status (hidden: 3 config, 1 .dirtygitignore)" {
+		t.Fatalf("title = %q", got)
+	}
+	if got := m.statusPaneTitle(12); len(got) > 14 {
+		t.Fatalf("title should be truncated to the pane, got %q", got)
+	}
+}
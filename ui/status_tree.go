@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+// statusTreeNode is one directory in the Status tree: its files and
+// subdirectories, keyed by name.
+type statusTreeNode struct {
+	// dir is the repo-relative directory path with a trailing slash ("" for the root).
+	dir     string
+	dirs    map[string]*statusTreeNode
+	entries []scanner.PorcelainEntry
+}
+
+func newStatusTreeNode(dir string) *statusTreeNode {
+	return &statusTreeNode{dir: dir, dirs: make(map[string]*statusTreeNode)}
+}
+
+// buildStatusTree files entries under their directories. An untracked
+// directory entry ("dir/") stays a leaf of its parent.
+func buildStatusTree(entries []scanner.PorcelainEntry) *statusTreeNode {
+	root := newStatusTreeNode("")
+	for _, e := range entries {
+		n := root
+		parts := strings.Split(strings.TrimSuffix(e.Path, "/"), "/")
+		for _, d := range parts[:len(parts)-1] {
+			child, ok := n.dirs[d]
+			if !ok {
+				child = newStatusTreeNode(n.dir + d + "/")
+				n.dirs[d] = child
+			}
+			n = child
+		}
+		n.entries = append(n.entries, e)
+	}
+	return root
+}
+
+// allEntries returns every entry under n, recursively.
+func (n *statusTreeNode) allEntries() []scanner.PorcelainEntry {
+	out := append([]scanner.PorcelainEntry(nil), n.entries...)
+	for _, c := range n.dirs {
+		out = append(out, c.allEntries()...)
+	}
+	return out
+}
+
+// compact follows chains of directories that hold nothing but one
+// subdirectory, so "a/b/c/" shows as a single row.
+func (n *statusTreeNode) compact() *statusTreeNode {
+	for len(n.entries) == 0 && len(n.dirs) == 1 {
+		for _, c := range n.dirs {
+			n = c
+		}
+	}
+	return n
+}
+
+// statusColumnCounts summarizes entries for a directory row: worktree changes
+// ("~modified ?untracked") and staged changes ("+staged").
+func statusColumnCounts(entries []scanner.PorcelainEntry) (worktree, staged string) {
+	nStaged, nModified, nUntracked := countEntries(entries)
+	var parts []string
+	if nModified > 0 {
+		parts = append(parts, fmt.Sprintf("~%d", nModified))
+	}
+	if nUntracked > 0 {
+		parts = append(parts, fmt.Sprintf("?%d", nUntracked))
+	}
+	if nStaged > 0 {
+		staged = fmt.Sprintf("+%d", nStaged)
+	}
+	return strings.Join(parts, " "), staged
+}
+
+// statusEntryRow is the table row for one file entry, with label as the path text.
+func statusEntryRow(entry scanner.PorcelainEntry, label string) table.Row {
+	if entry.OriginalPath != "" {
+		label = fmt.Sprintf("%s -> %s", entry.OriginalPath, label)
+	}
+	return table.Row{statusCodeLabel(entry.Worktree), statusCodeLabel(entry.Staging), label}
+}
+
+// statusRows collects Status table rows with their repo-relative paths,
+// whether each row is a directory, and the entries under each directory row.
+type statusRows struct {
+	rows       []table.Row
+	paths      []string
+	dirs       []bool
+	dirEntries map[string][]scanner.PorcelainEntry
+}
+
+func (r *statusRows) add(row table.Row, path string, dir bool) {
+	r.rows = append(r.rows, row)
+	r.paths = append(r.paths, path)
+	r.dirs = append(r.dirs, dir)
+}
+
+// appendStatusTreeRows adds rows for n's subdirectories (then its files) at
+// depth, skipping the contents of collapsed directories. Directory rows carry
+// their path with a trailing slash.
+func (m *model) appendStatusTreeRows(out *statusRows, repo string, n *statusTreeNode, depth int) {
+	names := make([]string, 0, len(n.dirs))
+	for name := range n.dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	indent := strings.Repeat("  ", depth)
+	for _, name := range names {
+		child := n.dirs[name].compact()
+		collapsed := m.statusDirCollapsed(repo, child.dir)
+		marker := "▾ "
+		if collapsed {
+			marker = "▸ "
+		}
+		entries := child.allEntries()
+		worktree, staged := statusColumnCounts(entries)
+		label := strings.TrimPrefix(child.dir, n.dir)
+		out.add(table.Row{worktree, staged, indent + marker + label}, child.dir, true)
+		if out.dirEntries == nil {
+			out.dirEntries = make(map[string][]scanner.PorcelainEntry)
+		}
+		out.dirEntries[child.dir] = entries
+		if !collapsed {
+			m.appendStatusTreeRows(out, repo, child, depth+1)
+		}
+	}
+	for _, e := range n.entries {
+		out.add(statusEntryRow(e, indent+"  "+path.Base(e.Path)+trailingSlash(e.Path)), e.Path, false)
+	}
+}
+
+// trailingSlash returns "/" when p names a directory (git's untracked-dir form).
+func trailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return "/"
+	}
+	return ""
+}
+
+// statusDirCollapsed reports whether dir is collapsed in repo's Status tree.
+func (m *model) statusDirCollapsed(repo, dir string) bool {
+	return m.statusCollapsed[repo][dir]
+}
+
+// selectedStatusDir returns the directory row under the Status cursor, if any.
+func (m *model) selectedStatusDir() (string, bool) {
+	if !m.statusTree || !m.statusFileSelected {
+		return "", false
+	}
+	i := m.statusTable.Cursor()
+	if i < 0 || i >= len(m.statusDirRows) || !m.statusDirRows[i] {
+		return "", false
+	}
+	return m.statusPaths[i], true
+}
+
+// statusDirOpPaths returns, when the selected Status row is the directory dir,
+// the paths of the entries listed under it that keep accepts (all when keep is
+// nil): file operations on a directory row act on those alone, leaving files
+// without changes untouched. It returns nil on a file row.
+func (m *model) statusDirOpPaths(dir string, keep func(scanner.PorcelainEntry) bool) []string {
+	if _, isDir := m.selectedStatusDir(); !isDir {
+		return nil
+	}
+	paths := []string{}
+	for _, e := range m.statusDirEntries[dir] {
+		if keep == nil || keep(e) {
+			paths = append(paths, e.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// entryInHead reports whether e's path exists in HEAD, so git checkout HEAD
+// can restore it.
+func entryInHead(e scanner.PorcelainEntry) bool {
+	switch e.Staging {
+	case '?', 'A', 'R', 'C':
+		return false
+	}
+	return true
+}
+
+// setStatusDirCollapsed collapses or expands dir in the current repo's tree.
+func (m *model) setStatusDirCollapsed(dir string, collapsed bool) {
+	repo := m.currentRepo()
+	if m.statusCollapsed == nil {
+		m.statusCollapsed = make(map[string]map[string]bool)
+	}
+	if m.statusCollapsed[repo] == nil {
+		m.statusCollapsed[repo] = make(map[string]bool)
+	}
+	m.statusCollapsed[repo][dir] = collapsed
+	m.syncViewports()
+}
+
+// handleStatusTreeArrow expands (→) or collapses (←) the selected directory.
+// ← on a file or an already collapsed directory selects its parent directory.
+// It reports false when the key should keep its usual meaning.
+func (m *model) handleStatusTreeArrow(right bool) bool {
+	if !m.statusTree || m.focus != paneStatus || !m.statusFileSelected {
+		return false
+	}
+	dir, isDir := m.selectedStatusDir()
+	if right {
+		if !isDir || !m.statusDirCollapsed(m.currentRepo(), dir) {
+			return false
+		}
+		m.setStatusDirCollapsed(dir, false)
+		return true
+	}
+	if isDir && !m.statusDirCollapsed(m.currentRepo(), dir) {
+		m.setStatusDirCollapsed(dir, true)
+		return true
+	}
+	// Select the nearest directory row above that contains the selection.
+	cur := m.statusTable.Cursor()
+	sel := m.statusPaths[cur]
+	for i := cur - 1; i >= 0; i-- {
+		if m.statusDirRows[i] && strings.HasPrefix(sel, m.statusPaths[i]) && sel != m.statusPaths[i] {
+			m.statusTable.SetCursor(i)
+			m.diffNeedsRefresh = true
+			m.syncViewports()
+			return true
+		}
+	}
+	return true
+}
+
+// toggleStatusTree switches the Status pane between the flat list and the tree.
+func (m *model) toggleStatusTree() {
+	selected := m.selectedStatusPath()
+	m.statusTree = !m.statusTree
+	m.refreshStatusContent()
+	m.selectStatusPath(selected)
+	if m.statusTree {
+		log.Printf("status: tree view")
+	} else {
+		log.Printf("status: flat list")
+	}
+	m.diffNeedsRefresh = true
+	m.syncViewports()
+}
+
+// selectStatusPath moves the Status cursor to path when it is listed.
+func (m *model) selectStatusPath(path string) {
+	if path == "" {
+		return
+	}
+	for i, p := range m.statusPaths {
+		if p == path {
+			m.statusTable.SetCursor(i)
+			return
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestStatusTreeRowsAndCollapse(t *testing.T) {
+	m := newTestModel()
+	m.width = 100
+	m.height = 30
+	m.repositories.AddResult("/r", scanner.RepoStatus{Porcelain: scanner.PorcelainStatus{Entries: []scanner.PorcelainEntry{
+		{Staging: ' ', Worktree: 'M', Path: "README.md"},
+		{Staging: 'M', Worktree: ' ', Path: "pkg/api/v1/a.go"},
+		{Staging: ' ', Worktree: 'M', Path: "pkg/api/v1/b.go"},
+		{Staging: '?', Worktree: '?', Path: "pkg/web/"},
+	}}})
+	m.repoList = m.visibleRepoPaths()
+	m.focus = paneStatus
+
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}}); !handled || !m.statusTree {
+		t.Fatal("T should switch to the tree view")
+	}
+	m.refreshStatusContent()
+	want := []string{"pkg/", "pkg/api/v1/", "pkg/api/v1/a.go", "pkg/api/v1/b.go", "pkg/web/", "README.md"}
+	if strings.Join(m.statusPaths, ",") != strings.Join(want, ",") {
+		t.Fatalf("tree paths = %v", m.statusPaths)
+	}
+	rows := m.statusTable.Rows()
+	if rows[0][0] != "~1 ?1" || rows[0][1] != "+1" || rows[0][2] != "▾ pkg/" {
+		t.Fatalf("pkg row = %q", rows[0])
+	}
+	if rows[1][2] != "  ▾ api/v1/" || rows[2][2] != "      a.go" {
+		t.Fatalf("nested rows = %q / %q", rows[1][2], rows[2][2])
+	}
+
+	// ← on a file selects its directory; ← again collapses it; → expands.
+	m.statusFileSelected = true
+	m.statusTable.SetCursor(3)
+	m.handleArrowKey(tea.KeyMsg{Type: tea.KeyLeft})
+	if got := m.selectedStatusPath(); got != "pkg/api/v1/" {
+		t.Fatalf("← from file selected %q", got)
+	}
+	m.handleArrowKey(tea.KeyMsg{Type: tea.KeyLeft})
+	if len(m.statusPaths) != 4 || !m.statusDirCollapsed("/r", "pkg/api/v1/") {
+		t.Fatalf("collapsed paths = %v", m.statusPaths)
+	}
+	if path, ok := m.selectedStatusPathForOps(); !ok || path != "pkg/api/v1/" {
+		t.Fatalf("ops path = %q, %v", path, ok)
+	}
+	m.handleArrowKey(tea.KeyMsg{Type: tea.KeyRight})
+	if len(m.statusPaths) != 6 || m.focus != paneStatus {
+		t.Fatalf("→ should expand: paths=%v focus=%v", m.statusPaths, m.focus)
+	}
+
+	m.statusTable.SetCursor(2)
+	m.toggleStatusTree()
+	if m.statusTree || len(m.statusPaths) != 4 || m.selectedStatusPath() != "pkg/api/v1/a.go" {
+		t.Fatalf("flat list: paths=%v selected=%q", m.statusPaths, m.selectedStatusPath())
+	}
+}
+
+func TestStatusTreeDirectoryOps(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "r")
+	runGitT(t, filepath.Dir(repo), "init", "-q", repo)
+	write := func(f, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(repo, filepath.Dir(f)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, f), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("gen/keep.txt", "keep\n")
+	write("gen/mod.txt", "base\n")
+	runGitT(t, repo, "add", ".")
+	runGitT(t, repo, "commit", "-qm", "base")
+	write("gen/mod.txt", "changed\n")
+	write("gen/added.txt", "added\n")
+	runGitT(t, repo, "add", "gen/added.txt")
+	write("gen/new.txt", "new\n")
+
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.focus = paneStatus
+	m.statusTree = true
+	m.statusFileSelected = true
+	m.syncViewports()
+	key := func(k string) {
+		t.Helper()
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	confirm := func(handle func(tea.KeyMsg) (tea.Model, tea.Cmd)) {
+		t.Helper()
+		m.deleteConfirmYes = true
+		handle(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	exists := func(f string) bool {
+		_, err := os.Stat(filepath.Join(repo, f))
+		return err == nil
+	}
+	if m.selectedStatusPath() != "gen/" {
+		t.Fatalf("selected %q, want the directory row", m.selectedStatusPath())
+	}
+
+	// C restores only the listed tracked entries; new files stay.
+	key("C")
+	if got := strings.Join(m.checkoutStatusFilePendingPaths, ","); got != "gen/mod.txt" {
+		t.Fatalf("checkout paths = %q", got)
+	}
+	confirm(m.handleCheckoutStatusFileConfirmKey)
+	if b, _ := os.ReadFile(filepath.Join(repo, "gen/mod.txt")); string(b) != "base\n" {
+		t.Fatalf("mod.txt = %q after C", b)
+	}
+	if !exists("gen/added.txt") || !exists("gen/new.txt") {
+		t.Fatal("C should leave untracked and newly added files")
+	}
+
+	// a stages the listed entries.
+	key("a")
+	out := runGitT(t, repo, "diff", "--cached", "--name-only")
+	if strings.TrimSpace(out) != "gen/added.txt\ngen/new.txt" {
+		t.Fatalf("staged = %q", out)
+	}
+
+	// D removes the listed entries, not the whole directory.
+	key("D")
+	if got := strings.Join(m.deleteStatusFilePendingPaths, ","); got != "gen/added.txt,gen/new.txt" {
+		t.Fatalf("delete paths = %q", got)
+	}
+	if overlay := m.renderDeleteStatusFileConfirmOverlay(); !strings.Contains(overlay, "under this directory") {
+		t.Fatalf("delete overlay should name the directory's changes: %q", overlay)
+	}
+	confirm(m.handleDeleteStatusFileConfirmKey)
+	if exists("gen/added.txt") || exists("gen/new.txt") {
+		t.Fatal("D should remove the listed entries")
+	}
+	if !exists("gen/keep.txt") || !exists("gen/mod.txt") {
+		t.Fatal("D should keep files without changes")
+	}
+}
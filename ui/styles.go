@@ -89,14 +89,25 @@ func focusedSectionTitle(focused bool, label string) string {
 
 // diff line styles for git output (see styleDiffContent).
 var (
-	diffStyleAdded   = lipgloss.NewStyle().Foreground(lipgloss.Color("#00D787"))
-	diffStyleDeleted = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555"))
+	diffStyleAdded   = lipgloss.NewStyle().Foreground(diffColorAdded)
+	diffStyleDeleted = lipgloss.NewStyle().Foreground(diffColorDeleted)
 	diffStyleHunk    = lipgloss.NewStyle().Foreground(lipgloss.Color("#00AFFF")).Bold(true)
 	diffStyleHeader  = lipgloss.NewStyle().Foreground(lipgloss.Color("#AF87FF")).Bold(true)
 	diffStyleFile    = lipgloss.NewStyle().Foreground(lipgloss.Color("#5F87FF"))
 	diffStyleMeta    = lipgloss.NewStyle().Foreground(lipgloss.Color("#808080"))
 )
 
+// Colours of -/+ lines, with the backgrounds for highlighted lines and for the
+// changed words inside them (see styleDiff); syntax colours take the foreground.
+var (
+	diffColorAdded        = lipgloss.Color("#00D787")
+	diffColorDeleted      = lipgloss.Color("#FF5555")
+	diffBgAdded           = lipgloss.Color("#00261A")
+	diffBgDeleted         = lipgloss.Color("#3A0000")
+	diffBgAddedEmphasis   = lipgloss.Color("#005F00")
+	diffBgDeletedEmphasis = lipgloss.Color("#870000")
+)
+
 // diffPaneTopBorderLabel builds the Diff pane top-border title segment.
 func diffPaneTopBorderLabel(diffPaneFocused bool, worktreeMode bool) string {
 	diffLbl := "Diff"
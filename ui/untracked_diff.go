@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+const (
+	// untrackedDiffMaxBytes is the largest untracked file shown in full; bigger
+	// files get a one-line summary.
+	untrackedDiffMaxBytes = 512 << 10
+	// untrackedDiffMaxFiles caps how many untracked files one diff renders.
+	untrackedDiffMaxFiles = 50
+	// binarySniffBytes is how much of a file is checked for NUL bytes, as git does.
+	binarySniffBytes = 8000
+)
+
+// untrackedFiles expands the untracked directory entry dir into its files,
+// skipping those git ignores.
+func untrackedFiles(repo, dir string) ([]string, error) {
+	out, err := runGitInRepo(repo, "ls-files", "--others", "--exclude-standard", "-z", "--", dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for f := range strings.SplitSeq(out, "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// untrackedDiff renders untracked status entries as new-file diffs. An
+// untracked directory entry ("dir/") shows each file in it.
+func untrackedDiff(repo string, entries []string) string {
+	var files []string
+	for _, e := range entries {
+		if !strings.HasSuffix(e, "/") {
+			files = append(files, e)
+			continue
+		}
+		in, err := untrackedFiles(repo, e)
+		if err != nil {
+			log.Printf("git: %v", err)
+			continue
+		}
+		files = append(files, in...)
+	}
+	var b strings.Builder
+	for i, rel := range files {
+		if i == untrackedDiffMaxFiles {
+			fmt.Fprintf(&b, "(%d more untracked files not shown)\n", len(files)-i)
+			break
+		}
+		b.WriteString(untrackedFileDiff(repo, rel))
+	}
+	return b.String()
+}
+
+// selectedUntrackedEntries returns the untracked Status entries the Diff pane
+// shows for the row showing path: the row itself when it is untracked, or the
+// untracked entries listed under a directory row. With no row selected it
+// returns none, so the whole-repository diff never lists untracked files.
+func (m *model) selectedUntrackedEntries(repo, path string) []string {
+	if path == "" {
+		return nil
+	}
+	var entries []scanner.PorcelainEntry
+	if _, isDir := m.selectedStatusDir(); isDir {
+		entries = m.statusDirEntries[path]
+	} else if st, ok := m.repositories.Get(repo); ok {
+		for _, e := range st.Porcelain.Entries {
+			if e.Path == path {
+				entries = append(entries, e)
+				break
+			}
+		}
+	}
+	var out []string
+	for _, e := range entries {
+		if e.Staging == '?' {
+			out = append(out, e.Path)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// untrackedFileDiff is git diff --no-index /dev/null <file>, or a summary line
+// under the diff header for binary files and files over untrackedDiffMaxBytes.
+func untrackedFileDiff(repo, rel string) string {
+	header := fmt.Sprintf("diff --git a/%s b/%s\n", rel, rel)
+	full := filepath.Join(repo, rel)
+	fi, err := os.Lstat(full)
+	if err != nil {
+		return header + fmt.Sprintf("(untracked file: %v)\n", err)
+	}
+	if fi.Mode().IsRegular() {
+		if fi.Size() > untrackedDiffMaxBytes {
+			return header + fmt.Sprintf("(new file, %s, over the %s display limit; not shown)\n",
+				formatByteSize(fi.Size()), formatByteSize(untrackedDiffMaxBytes))
+		}
+		if isBinaryFile(full) {
+			return header + fmt.Sprintf("(new binary file, %s; not shown)\n", formatByteSize(fi.Size()))
+		}
+	}
+	// Plain patch flags, as for the tracked diff this is appended to.
+	out, err := runGitPatch(repo, "diff", "--no-index", "--", os.DevNull, rel)
+	// --no-index exits 1 when the files differ, which they always do here.
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() != 1) {
+		return header + fmt.Sprintf("(git diff --no-index failed: %v)\n", err)
+	}
+	return out
+}
+
+// isBinaryFile reports whether the start of the file contains a NUL byte.
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	buf := make([]byte, binarySniffBytes)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return false
+	}
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
+
+// formatByteSize writes n in B, KiB or MiB.
+func formatByteSize(n int64) string {
+	switch {
+	case n < 1<<10:
+		return fmt.Sprintf("%d B", n)
+	case n < 1<<20:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	}
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestUntrackedDiff(t *testing.T) {
+	repo := lineFixtureRepo(t, lineFixtureBase, lineFixtureBase)
+	runGitT(t, repo, "config", "color.ui", "always")
+	runGitT(t, repo, "config", "diff.noprefix", "true")
+	write := func(rel string, data []byte) {
+		t.Helper()
+		full := filepath.Join(repo, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("gen/notes.txt", []byte("hello\nworld\n"))
+	write("gen/logo.png", []byte("\x89PNG\x00\x01\x02"))
+	write("gen/skip.log", []byte("ignored\n"))
+	write(".gitignore", []byte("*.log\n"))
+	write("dump.sql", []byte(strings.Repeat("x", 2*untrackedDiffMaxBytes)))
+
+	got := untrackedDiff(repo, []string{"gen/"})
+	for _, want := range []string{
+		"diff --git a/gen/notes.txt b/gen/notes.txt",
+		"+++ b/gen/notes.txt",
+		"+hello\n+world\n",
+		"diff --git a/gen/logo.png b/gen/logo.png\n(new binary file, 7 B; not shown)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("untrackedDiff(gen/) lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "skip.log") {
+		t.Errorf("ignored files should not be shown:\n%s", got)
+	}
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("untracked diffs should be plain:\n%q", got)
+	}
+	if got := untrackedDiff(repo, []string{"dump.sql"}); !strings.Contains(got, "(new file, 1.0 MiB, over the 512.0 KiB display limit; not shown)") {
+		t.Errorf("oversized file:\n%s", got)
+	}
+}
+
+func TestDiffPaneShowsUntrackedFile(t *testing.T) {
+	repo := lineFixtureRepo(t, lineFixtureBase, lineFixtureWork)
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("fresh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &scanner.Config{}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.syncViewports()
+
+	// With no file selected, only the tracked change shows.
+	if !strings.Contains(m.diffRaw, "+\tx := 2") || strings.Contains(m.diffRaw, "+fresh") || len(m.diffHunks) != 1 {
+		t.Fatalf("hunks %d, diff:\n%s", len(m.diffHunks), m.diffRaw)
+	}
+
+	selectPath := func(path string) {
+		t.Helper()
+		m.statusTable.SetCursor(slices.Index(m.statusPaths, path))
+		m.focus = paneStatus
+		m.statusFileSelected = true
+		m.diffNeedsRefresh = true
+		m.syncViewports()
+	}
+	selectPath("new.txt")
+	if !strings.HasPrefix(m.diffRaw, "diff --git a/new.txt b/new.txt") || strings.Contains(m.diffRaw, "m.go") {
+		t.Fatalf("diff for new.txt:\n%s", m.diffRaw)
+	}
+	selectPath("m.go")
+	if strings.Contains(m.diffRaw, "+fresh") {
+		t.Fatalf("a tracked file should not list untracked files:\n%s", m.diffRaw)
+	}
+}
+
+func TestDiffPaneShowsUntrackedUnderDirectoryRow(t *testing.T) {
+	repo := lineFixtureRepo(t, lineFixtureBase, lineFixtureBase)
+	for _, f := range []string{"gen/tracked.txt", "gen/a.txt", "gen/sub/b.txt", "gen/skip.tmp"} {
+		if err := os.MkdirAll(filepath.Join(repo, filepath.Dir(f)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, f), []byte(f+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if f == "gen/tracked.txt" {
+			runGitT(t, repo, "add", f)
+			runGitT(t, repo, "commit", "-qm", "tracked")
+		}
+	}
+	// dirtygit's own exclude rules drop skip.tmp, which git still lists.
+	cfg := &scanner.Config{}
+	cfg.GitIgnore.FileGlob = []string{"*.tmp"}
+	rs, _, err := scanner.StatusForRepo(cfg, repo)
+	if err != nil {
+		t.Fatalf("StatusForRepo: %v", err)
+	}
+	m := newTestModel()
+	m.config = cfg
+	m.width = 120
+	m.height = 40
+	m.repositories.AddResult(repo, rs)
+	m.repoList = []string{repo}
+	m.statusTree = true
+	m.focus = paneStatus
+	m.statusFileSelected = true
+	m.syncViewports()
+
+	if m.selectedStatusPath() != "gen/" {
+		t.Fatalf("selected %q, statusPaths %v", m.selectedStatusPath(), m.statusPaths)
+	}
+	for _, want := range []string{"+gen/a.txt", "+gen/sub/b.txt"} {
+		if !strings.Contains(m.diffRaw, want) {
+			t.Errorf("directory row diff lacks %q:\n%s", want, m.diffRaw)
+		}
+	}
+	if strings.Contains(m.diffRaw, "skip.tmp") {
+		t.Errorf("excluded files should not be shown:\n%s", m.diffRaw)
+	}
+}
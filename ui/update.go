@@ -59,12 +59,9 @@ func (m *model) finishScan(r scanResult) {
 	}
 
 	m.repositories = r.mgs
-	m.repoList = r.mgs.SortedRepoPaths()
+	m.rebuildRepoList()
 	m.statusFileSelected = false
 	m.diffNeedsRefresh = true
-	if m.cursor >= len(m.repoList) {
-		m.cursor = max(0, len(m.repoList)-1)
-	}
 }
 
 // handleScanTick polls for scan completion and schedules the next poll.
@@ -139,16 +136,18 @@ func (m *model) handleCheckoutStatusFileConfirmKey(msg tea.KeyMsg) (tea.Model, t
 	case "esc":
 		m.checkoutStatusFileConfirmOpen = false
 		m.checkoutStatusFilePendingRel = ""
+		m.checkoutStatusFilePendingPaths = nil
 		return m, nil
 	case "enter":
 		m.checkoutStatusFileConfirmOpen = false
-		pending := m.checkoutStatusFilePendingRel
+		pending := pendingStatusPaths(m.checkoutStatusFilePendingRel, m.checkoutStatusFilePendingPaths)
 		m.checkoutStatusFilePendingRel = ""
-		if !m.deleteConfirmYes {
+		m.checkoutStatusFilePendingPaths = nil
+		if !m.deleteConfirmYes || len(pending) == 0 {
 			return m, nil
 		}
 		repo := m.currentRepo()
-		if err := gitCheckoutHeadPath(repo, pending); err != nil {
+		if err := gitCheckoutHeadPath(repo, pending...); err != nil {
 			log.Printf("git: %v", err)
 		} else {
 			m.refreshRepoStatusAfterGit()
@@ -170,15 +169,17 @@ func (m *model) handleDeleteStatusFileConfirmKey(msg tea.KeyMsg) (tea.Model, tea
 	case "esc":
 		m.deleteStatusFileConfirmOpen = false
 		m.deleteStatusFilePendingRel = ""
+		m.deleteStatusFilePendingPaths = nil
 		return m, nil
 	case "enter":
 		m.deleteStatusFileConfirmOpen = false
-		pending := m.deleteStatusFilePendingRel
+		pending := pendingStatusPaths(m.deleteStatusFilePendingRel, m.deleteStatusFilePendingPaths)
 		m.deleteStatusFilePendingRel = ""
+		m.deleteStatusFilePendingPaths = nil
 		if !m.deleteConfirmYes {
 			return m, nil
 		}
-		m.deletePendingStatusFileFromDisk(pending)
+		m.deletePendingStatusFileFromDisk(pending...)
 		return m, nil
 	default:
 		m.handleConfirmNavKey(msg)
@@ -186,15 +187,26 @@ func (m *model) handleDeleteStatusFileConfirmKey(msg tea.KeyMsg) (tea.Model, tea
 	}
 }
 
-// deletePendingStatusFileFromDisk removes the given repo-relative path after confirmation.
-func (m *model) deletePendingStatusFileFromDisk(gitRelPath string) {
+// pendingStatusPaths returns the paths a status operation acts on: the
+// directory row's entry paths when set, else rel alone.
+func pendingStatusPaths(rel string, paths []string) []string {
+	if paths != nil || rel == "" {
+		return paths
+	}
+	return []string{rel}
+}
+
+// deletePendingStatusFileFromDisk removes the given repo-relative paths after confirmation.
+func (m *model) deletePendingStatusFileFromDisk(gitRelPaths ...string) {
 	repo := m.currentRepo()
-	if repo == "" || gitRelPath == "" {
+	if repo == "" || len(gitRelPaths) == 0 {
 		return
 	}
-	if err := removeStatusPathOnDisk(repo, gitRelPath); err != nil {
-		log.Printf("remove status path: %v", err)
-		return
+	for _, p := range gitRelPaths {
+		if err := removeStatusPathOnDisk(repo, p); err != nil {
+			log.Printf("remove status path: %v", err)
+			break
+		}
 	}
 	m.refreshRepoStatusAfterGit()
 	m.diffNeedsRefresh = true
@@ -213,10 +225,7 @@ func (m *model) deleteSelectedRepoFromDisk() {
 		return
 	}
 	m.repositories.Delete(repo)
-	m.repoList = m.repositories.SortedRepoPaths()
-	if m.cursor >= len(m.repoList) {
-		m.cursor = max(0, len(m.repoList)-1)
-	}
+	m.rebuildRepoList()
 	m.statusFileSelected = false
 	m.diffNeedsRefresh = true
 	m.syncViewports()
@@ -242,7 +251,8 @@ func (m *model) toggleZoom() {
 	m.zoomTarget = m.focus
 }
 
-// tabFocusCycle is the Tab / Shift+Tab order. Diff is not included; focus it by clicking the pane.
+// tabFocusCycle is the Tab / Shift+Tab order. Diff is not included; focus it by
+// clicking the pane. paneBranches stands for whichever pane is below Status.
 var tabFocusCycle = []pane{paneRepo, paneStatus, paneBranches, paneLog}
 
 // cycleFocus moves focus across panes in forward or reverse order (skipping Diff).
@@ -252,6 +262,9 @@ func (m *model) cycleFocus(forward bool) {
 	if m.zoomed {
 		cur = m.zoomTarget
 	}
+	if cur == paneStash {
+		cur = paneBranches
+	}
 
 	var i int
 	found := false
@@ -282,6 +295,9 @@ func (m *model) cycleFocus(forward bool) {
 	}
 
 	next := tabFocusCycle[i]
+	if next == paneBranches {
+		next = m.lowerLeftPane()
+	}
 	if m.zoomed {
 		m.zoomTarget = next
 		m.focus = next
@@ -292,10 +308,20 @@ func (m *model) cycleFocus(forward bool) {
 
 // handleCommandKey handles global command keys and focus controls.
 func (m *model) handleCommandKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	if cmd, ok := m.handleStashKey(msg); ok {
+		return m, cmd, true
+	}
 	switch msg.String() {
 	case "ctrl+c", "q":
 		return m, tea.Quit, true
 	case "esc":
+		if m.filterQuery(m.focus) != "" {
+			m.setFilter(m.focus, "")
+			return m, nil, true
+		}
+		if m.focus == paneDiff && m.clearHunkSelection() {
+			return m, nil, true
+		}
 		if m.zoomed {
 			m.zoomed = false
 			m.syncViewports()
@@ -332,10 +358,64 @@ func (m *model) handleCommandKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
 	case "t":
 		m.openTerminalInCurrentRepo()
 		return m, nil, true
+	case "L":
+		return m, nil, m.openLostCommits()
+	case "c":
+		cmd, ok := m.openCommit()
+		return m, cmd, ok
+	case "p":
+		return m, nil, m.openPush()
+	case "m":
+		return m, nil, m.openBranchMenu()
+	case "R":
+		return m, nil, m.toggleCompareDirection()
+	case "$":
+		return m, nil, m.toggleStashPane()
+	case "w":
+		if !m.repoPaneReady() {
+			return m, nil, false
+		}
+		m.whyOpen = true
+		return m, nil, true
+	case "/":
+		return m, nil, m.openFilter()
+	case "n", "N":
+		return m, nil, m.jumpFilterMatch(msg.String() == "N")
+	case "o", "O":
+		if m.err != nil {
+			return m, nil, false
+		}
+		m.cycleRepoSort(msg.String() == "O")
+		return m, nil, true
+	case "T":
+		if m.err != nil {
+			return m, nil, false
+		}
+		m.toggleStatusTree()
+		return m, nil, true
+	case "g":
+		if m.err != nil {
+			return m, nil, false
+		}
+		m.cycleRepoGroup()
+		return m, nil, true
+	case "b":
+		if m.err != nil {
+			return m, nil, false
+		}
+		m.toggleShowBehind()
+		return m, nil, true
+	case "P":
+		if m.err != nil {
+			return m, nil, false
+		}
+		m.openMergedBranches()
+		return m, nil, true
 	case "D":
 		if path, ok := m.selectedStatusPathForOps(); ok {
 			m.deleteStatusFileConfirmOpen = true
 			m.deleteStatusFilePendingRel = path
+			m.deleteStatusFilePendingPaths = m.statusDirOpPaths(path, nil)
 			m.deleteConfirmYes = false
 			return m, nil, true
 		}
@@ -345,14 +425,23 @@ func (m *model) handleCommandKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
 			return m, nil, true
 		}
 		return m, nil, false
+	case "]", "[":
+		return m, nil, m.moveHunkCursor(msg.String() == "[")
+	case "v":
+		return m, nil, m.toggleLineMode()
 	case "a", "r":
+		if m.hunkOpsReady() {
+			m.applySelectedHunk(msg.String())
+			return m, nil, true
+		}
 		if path, ok := m.selectedStatusPathForOps(); ok {
 			repo := m.currentRepo()
+			paths := pendingStatusPaths(path, m.statusDirOpPaths(path, nil))
 			var err error
 			if msg.String() == "a" {
-				err = gitAdd(repo, path)
+				err = gitAdd(repo, paths...)
 			} else {
-				err = gitResetPath(repo, path)
+				err = gitResetPath(repo, paths...)
 			}
 			if err != nil {
 				log.Printf("git: %v", err)
@@ -364,13 +453,29 @@ func (m *model) handleCommandKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
 			return m, nil, true
 		}
 	case "C":
+		if m.hunkOpsReady() {
+			m.openDiscardHunkConfirm()
+			return m, nil, true
+		}
 		if path, ok := m.selectedStatusPathForOps(); ok {
+			paths := m.statusDirOpPaths(path, entryInHead)
+			if paths != nil && len(paths) == 0 {
+				log.Printf("checkout: nothing under %s is in HEAD", path)
+				return m, nil, true
+			}
 			m.checkoutStatusFileConfirmOpen = true
 			m.checkoutStatusFilePendingRel = path
+			m.checkoutStatusFilePendingPaths = paths
 			m.deleteConfirmYes = false
 			return m, nil, true
 		}
 	case " ":
+		if m.focus == paneRepo && m.toggleSelectedGroup() {
+			return m, nil, true
+		}
+		if m.focus == paneBranches && m.toggleBranchDetails() {
+			return m, nil, true
+		}
 		if m.focus != paneDiff && m.focus != paneStatus {
 			return m, nil, false
 		}
@@ -474,7 +579,28 @@ func (m *model) handleVerticalArrowKey(msg tea.KeyMsg, step int, up, down bool)
 		return m.repoListVerticalArrow(step, up, down)
 	case paneStatus:
 		return m.statusVerticalArrow(msg, step, up)
+	case paneBranches:
+		if up {
+			m.branchTable.MoveUp(step)
+		} else {
+			m.branchTable.MoveDown(step)
+		}
+		// Moving onto or off a remote or commit row changes what Diff shows.
+		m.syncViewports()
+		return m, nil, true
+	case paneStash:
+		if up {
+			m.stashTable.MoveUp(step)
+		} else {
+			m.stashTable.MoveDown(step)
+		}
+		m.syncViewports()
+		return m, nil, true
 	case paneDiff:
+		if m.diffLineMode {
+			m.moveLineCursor(up)
+			return m, nil, true
+		}
 		var cmd tea.Cmd
 		m.diffVP, cmd = viewportVerticalKey(m.diffVP, msg, step, up)
 		return m, cmd, true
@@ -501,11 +627,17 @@ func (m *model) handleArrowKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
 		down := msg.Type == tea.KeyDown || msg.Type == tea.KeyShiftDown
 		return m.handleVerticalArrowKey(msg, step, up, down)
 	case tea.KeyRight:
+		if m.handleStatusTreeArrow(true) {
+			return m, nil, true
+		}
 		if m.focus == paneStatus {
 			m.focusStatusDiff(true)
 			return m, nil, true
 		}
 	case tea.KeyLeft:
+		if m.handleStatusTreeArrow(false) {
+			return m, nil, true
+		}
 		if m.focus == paneDiff {
 			m.focusStatusDiff(false)
 			return m, nil, true
@@ -554,6 +686,9 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.helpOpen {
 		return m.handleHelpOverlayKey(msg)
 	}
+	if m.whyOpen {
+		return m.handleWhyKey(msg)
+	}
 	if m.deleteRepoConfirmOpen {
 		return m.handleDeleteRepoConfirmKey(msg)
 	}
@@ -563,6 +698,33 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.checkoutStatusFileConfirmOpen {
 		return m.handleCheckoutStatusFileConfirmKey(msg)
 	}
+	if m.discardHunkConfirmOpen {
+		return m.handleDiscardHunkConfirmKey(msg)
+	}
+	if m.lostCommitsOpen {
+		return m.handleLostCommitsKey(msg)
+	}
+	if m.mergedBranchesOpen {
+		return m.handleMergedBranchesKey(msg)
+	}
+	if m.commitOpen {
+		return m.handleCommitKey(msg)
+	}
+	if m.pushOpen {
+		return m.handlePushKey(msg)
+	}
+	if m.branchMenuOpen {
+		return m.handleBranchMenuKey(msg)
+	}
+	if m.stashCreateOpen {
+		return m.handleStashCreateKey(msg)
+	}
+	if m.stashDropConfirmOpen {
+		return m.handleStashDropConfirmKey(msg)
+	}
+	if m.filterEditing {
+		return m.handleFilterKey(msg)
+	}
 	if m.scanning {
 		return m.handleScanningKey(msg)
 	}
@@ -650,6 +812,18 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tickMsg:
 		return m.handleScanTick()
 
+	case mergedBranchesMsg:
+		return m.handleMergedBranchesMsg(msg)
+
+	case commitEditorDoneMsg:
+		return m.handleCommitEditorDone(msg)
+
+	case pushProgressMsg:
+		return m.handlePushProgress(msg)
+
+	case pushDoneMsg:
+		return m.handlePushDone(msg)
+
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 
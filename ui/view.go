@@ -115,22 +115,49 @@ func (m *model) helpPanel() string {
 		"Shift+Tab     Previous pane; when zoomed, cycle backward",
 		"Enter         Zoom focused pane; Enter again restores the split layout",
 		"Esc           Exit zoom, or clear Status file selection; also closes this help",
-		"↑ / ↓         Move repo selection or scroll Status / Diff / Log",
+		"↑ / ↓         Move repo or branch selection, or scroll Status / Diff / Log",
 		"← / →         Status focused: → focuses Diff; Diff focused: ← focuses Status",
 		"Shift+↑/↓     Same, in steps of 10 lines",
-		"Space         In Status or Diff: toggle Worktree vs Staged diff",
+		"Space         In Status or Diff: toggle Worktree vs Staged diff; in Branches: list the selected branch's",
+		"              differing remote copies (select one to diff it in Diff) and unpushed commits (git show)",
+		"R             Branches on a remote copy row: switch the diff between the local and the remote side",
+		"$             Swap Branches for the Stash pane (and back); Stash focused: n new stash (Tab: with untracked),",
+		"              a apply, p pop, d drop (confirm); the selected entry's diff shows in Diff",
 		"a  r          With a file row selected (Status or Diff): git add / git reset (unstage) that path",
 		"C             With a file row selected (Status or Diff): restore file to last commit (confirms git checkout HEAD -- path)",
+		"]  [          Diff focused: select the next / previous hunk; a stages it, r unstages it (Staged diff),",
+		"              C discards it from the worktree (confirm); Esc drops the hunk selection",
+		"v             Diff with a hunk selected: select lines in it (↑/↓ extend); a r C then act on those lines",
+		"c             Commit the staged changes: message editor, amend toggle, or $GIT_EDITOR",
+		"p             Branches focused: push the selected branch (Tab: all unpushed branches) to a chosen remote,",
+		"              setting the upstream when missing; git's progress goes to the Log",
+		"m             Branches focused: branch actions (check out, new, rename, delete -d / -D, set / unset upstream)",
 		"s             Scan / rescan",
 		"e             Open selected repository (edit.command in config)",
 		"t             Open a new terminal in the selected repo (from TERM_PROGRAM when known)",
+		"w             Repositories focused: why this repository is listed (reasons from the rules config)",
+		"o  O          Next / previous repository sort: path, last commit, changed files, unpushed commits,",
+		"              last modified, dirty since, oldest unpushed, risk score",
+		"/             Filter the focused pane (Repositories: paths, branches, changed files; Status; Branches)",
+		"              Enter keeps the filter, Esc clears it; in Repositories n / N jump between the matches",
+		"T             Status as a tree grouped by directory (→ expands, ← collapses or goes to the parent);",
+		"              a r D C on a directory row apply to everything under it",
+		"g             Group repositories: none, by scandirs.include root, by parent directory",
+		"              Space on a group header collapses / expands it",
+		"b             Show / hide repositories that are only behind their remote (↓N incoming, newest age)",
+		"L             Lost commits: reflog commits no ref reaches; Enter names a rescue branch at the selected one",
+		"P             Merged branches: Space marks, d deletes with git branch -d; Tab widens to all repos",
 		"D             Repo list: delete the repository directory (confirm)",
 		"              Status or Diff with a file row: delete that path under the repo (confirm)",
 		"q  Ctrl+C     Quit (works from this overlay too)",
 		"?  h          Toggle this help (also closes with ? or h)",
 		"",
-		"From help: Esc, ?, or h closes · q / Ctrl+C quits the app.",
 	}
+	lines = append(lines, repoColumnLegend...)
+	lines = append(lines,
+		"",
+		"From help: Esc, ?, or h closes · q / Ctrl+C quits the app.",
+	)
 	body := strings.Join(lines, "\n")
 	w, h := m.width, m.height
 	if h < 1 {
@@ -178,7 +205,14 @@ func (m *model) renderDeleteStatusFileConfirmOverlay() string {
 	repoLine := styleDim.Render(truncateASCII(repo, innerW))
 	relLine := styleDim.Render(truncateASCII(m.deleteStatusFilePendingRel, innerW))
 	t := styleBold.Render("Delete this file or directory from disk?")
-	warn := warnBlock(innerW).Render("This removes the path from your working tree (not only from git's index). This cannot be undone.")
+	msg := "This removes the path from your working tree (not only from git's index). This cannot be undone."
+	if n := len(m.deleteStatusFilePendingPaths); m.deleteStatusFilePendingPaths != nil {
+		t = styleBold.Render("Delete the changes under this directory from disk?")
+		msg = fmt.Sprintf("This removes the %d changed or untracked path(s) listed under the directory from your working tree; files without changes stay. This cannot be undone.", n)
+	} else if strings.HasSuffix(m.deleteStatusFilePendingRel, "/") {
+		msg = "This removes the whole untracked directory from your working tree. This cannot be undone."
+	}
+	warn := warnBlock(innerW).Render(msg)
 	if err != nil {
 		warn = warnBlock(innerW).Render(fmt.Sprintf("Invalid path: %v", err))
 	}
@@ -206,7 +240,11 @@ func (m *model) renderCheckoutStatusFileConfirmOverlay() string {
 	repoLine := styleDim.Render(truncateASCII(repo, innerW))
 	relLine := styleDim.Render(truncateASCII(m.checkoutStatusFilePendingRel, innerW))
 	t := styleBold.Render("Restore this path from the last commit?")
-	warn := warnBlock(innerW).Render("This runs git checkout HEAD -- on the path. Uncommitted changes (staged and unstaged) for this file will be discarded.")
+	msg := "This runs git checkout HEAD -- on the path. Uncommitted changes (staged and unstaged) for this file will be discarded."
+	if n := len(m.checkoutStatusFilePendingPaths); m.checkoutStatusFilePendingPaths != nil {
+		msg = fmt.Sprintf("This runs git checkout HEAD -- on the %d changed path(s) listed under the directory. Their uncommitted changes (staged and unstaged) will be discarded; untracked and newly added files stay.", n)
+	}
+	warn := warnBlock(innerW).Render(msg)
 	btns := deleteConfirmButtons(m.deleteConfirmYes)
 	inner := strings.Join([]string{
 		t, "",
@@ -263,8 +301,8 @@ func (m *model) framedMiddleRow(statusBody, branchBody, diffBody int, statusView
 	branchOuter := panelOuter(branchBody)
 	diffOuter := panelOuter(diffBody)
 	leftCol := lipgloss.JoinVertical(lipgloss.Left,
-		m.framedBlock(paneStatus, leftW, statusOuter, "Status", statusView),
-		m.framedBlock(paneBranches, leftW, branchOuter, "Branches", branchView),
+		m.framedBlock(paneStatus, leftW, statusOuter, m.withFilterTitle(paneStatus, m.statusPaneTitle(leftW-4)), statusView),
+		m.framedBlock(m.lowerLeftPane(), leftW, branchOuter, m.lowerLeftTitle(), branchView),
 	)
 	rightCol := m.framedBlock(paneDiff, rightW, diffOuter, "Diff", diffView)
 	return lipgloss.JoinHorizontal(lipgloss.Top, leftCol, rightCol)
@@ -315,6 +353,22 @@ func (m *model) syncRepoListScrollOnly() {
 	m.clampRepoScroll(lay.repo)
 }
 
+// repoListSuffix annotates repo list rows that are listed for reasons other
+// than local changes: no remote configured, or only behind a remote.
+func (m *model) repoListSuffix(path string) string {
+	st, ok := m.repositories.Get(path)
+	switch {
+	case !ok:
+		return ""
+	case st.NoRemote:
+		return fmt.Sprintf("  no remote, %d commits, last %s", st.CommitCount, relativeTime(st.LastCommitUnix))
+	case st.BehindOnly:
+		n, newest := st.Behind()
+		return fmt.Sprintf("  ↓%d %s", n, relativeTime(newest))
+	}
+	return ""
+}
+
 // repoListView renders the repository list with current selection styling.
 func (m *model) repoListView(innerH int) string {
 	selFocused := styleSelRowFocused
@@ -334,12 +388,13 @@ func (m *model) repoListView(innerH int) string {
 	if end > n {
 		end = n
 	}
+	cols := m.repoColumnLayout(m.innerWidth())
 	var b strings.Builder
 	for i := start; i < end; i++ {
 		if i > start {
 			b.WriteString("\n")
 		}
-		path := m.repoList[i]
+		path := m.repoListRow(m.repoList[i], m.innerWidth(), cols, i != m.cursor)
 		if i == m.cursor {
 			if m.focus == paneRepo {
 				b.WriteString(selFocused.Render(path))
@@ -368,11 +423,13 @@ func (m *model) renderScanOverlay() string {
 func (m *model) renderZoomedPane(lay paneLayout) string {
 	switch m.zoomTarget {
 	case paneRepo:
-		return m.framedBlock(paneRepo, m.width, m.height, "Repositories", m.repoListView(lay.repo))
+		return m.framedBlock(paneRepo, m.width, m.height, m.repoPaneTitle(), m.repoListView(lay.repo))
 	case paneStatus:
-		return m.framedBlock(paneStatus, m.width, m.height, "Status", m.statusTable.View())
+		return m.framedBlock(paneStatus, m.width, m.height, m.withFilterTitle(paneStatus, m.statusPaneTitle(m.width-4)), m.statusTable.View())
 	case paneBranches:
-		return m.framedBlock(paneBranches, m.width, m.height, "Branches", m.branchTable.View())
+		return m.framedBlock(paneBranches, m.width, m.height, m.withFilterTitle(paneBranches, "Branches"), m.branchTable.View())
+	case paneStash:
+		return m.framedBlock(paneStash, m.width, m.height, m.lowerLeftTitle(), m.stashTable.View())
 	case paneDiff:
 		return m.framedBlock(paneDiff, m.width, m.height, "Diff", m.diffVP.View())
 	case paneLog:
@@ -388,8 +445,8 @@ func (m *model) renderMainStack(lay paneLayout) string {
 	repoOuter := panelOuter(lay.repo)
 	logOuter := panelOuter(lay.logBody)
 
-	repoBlock := m.framedBlock(paneRepo, m.width, repoOuter, "Repositories", m.repoListView(lay.repo))
-	middleRow := m.framedMiddleRow(lay.status, lay.branch, lay.diff, m.statusTable.View(), m.branchTable.View(), m.diffVP.View())
+	repoBlock := m.framedBlock(paneRepo, m.width, repoOuter, m.repoPaneTitle(), m.repoListView(lay.repo))
+	middleRow := m.framedMiddleRow(lay.status, lay.branch, lay.diff, m.statusTable.View(), m.lowerLeftView(), m.diffVP.View())
 	m.setLogVPContent()
 	logBlock := m.framedBlock(paneLog, m.width, logOuter, "Log", m.logVP.View())
 
@@ -411,6 +468,9 @@ func (m *model) View() string {
 	if m.helpOpen {
 		return m.renderHelpOverlay()
 	}
+	if m.whyOpen {
+		return m.renderWhyOverlay()
+	}
 	if m.deleteRepoConfirmOpen {
 		return m.renderDeleteRepoConfirmOverlay()
 	}
@@ -420,6 +480,30 @@ func (m *model) View() string {
 	if m.checkoutStatusFileConfirmOpen {
 		return m.renderCheckoutStatusFileConfirmOverlay()
 	}
+	if m.discardHunkConfirmOpen {
+		return m.renderDiscardHunkConfirmOverlay()
+	}
+	if m.lostCommitsOpen {
+		return m.renderLostCommitsOverlay()
+	}
+	if m.mergedBranchesOpen {
+		return m.renderMergedBranchesOverlay()
+	}
+	if m.commitOpen {
+		return m.renderCommitOverlay()
+	}
+	if m.pushOpen {
+		return m.renderPushOverlay()
+	}
+	if m.branchMenuOpen {
+		return m.renderBranchMenuOverlay()
+	}
+	if m.stashCreateOpen {
+		return m.renderStashCreateOverlay()
+	}
+	if m.stashDropConfirmOpen {
+		return m.renderStashDropConfirmOverlay()
+	}
 	if m.height < layoutMinTermHeight {
 		return styleErr.Render(fmt.Sprintf("Need bigger screen (min height %d).", layoutMinTermHeight))
 	}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// handleWhyKey closes the why-listed overlay; q / Ctrl+C still quit.
+func (m *model) handleWhyKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "enter", "w":
+		m.whyOpen = false
+	}
+	return m, nil
+}
+
+// whyLines describes why the selected repository is listed: the policy
+// reasons, the current branch's remote mismatch, or incoming-only commits.
+func (m *model) whyLines() []string {
+	st, ok := m.repositories.Get(m.currentRepo())
+	if !ok {
+		return nil
+	}
+	var lines []string
+	for _, r := range st.Reasons {
+		line := "• " + r.Detail
+		if r.Unix > 0 {
+			line += styleDim.Render(" (" + relativeTime(r.Unix) + ")")
+		}
+		if r.Rule != "" {
+			line += styleDim.Render(" [" + r.Rule + "]")
+		}
+		lines = append(lines, line)
+	}
+	if reason, ok := st.LocalRemoteMismatchReason(); ok {
+		lines = append(lines, "", reason)
+	}
+	if st.Score.Total > 0 {
+		parts := make([]string, 0, len(st.Score.Components))
+		for _, sc := range st.Score.Components {
+			parts = append(parts, fmt.Sprintf("%s %d x %g", strings.ReplaceAll(sc.Name, "_", " "), sc.Count, sc.Weight))
+		}
+		lines = append(lines, "", fmt.Sprintf("Risk score %g: %s", st.Score.Total, strings.Join(parts, ", ")))
+	}
+	if st.BehindOnly {
+		n, newest := st.Behind()
+		lines = append(lines, fmt.Sprintf("• Only behind its remote: %d incoming commit(s), newest %s", n, relativeTime(newest)))
+	}
+	return lines
+}
+
+// renderWhyOverlay explains why the selected repository is in the list.
+func (m *model) renderWhyOverlay() string {
+	boxW := min(m.width-layoutModalSideGutter, layoutWhyAndConfirmModalMaxBox)
+	if boxW < layoutModalBoxMinWidth {
+		boxW = min(m.width-2, layoutModalBoxMinWidth)
+	}
+	innerW := max(layoutMinInnerContentWidth, boxW-layoutModalSideGutter)
+	t := styleBold.Render("Why is this repository listed?")
+	repoLine := styleDim.Render(truncateASCII(m.currentRepo(), innerW))
+	body := strings.Join(m.whyLines(), "\n")
+	if body == "" {
+		body = "No recorded reasons."
+	}
+	body = lipgloss.NewStyle().Width(innerW).Render(body)
+	footer := styleDim.Render("Esc, Enter, or w to close")
+	inner := strings.Join([]string{t, "", repoLine, "", body, "", footer}, "\n")
+	return m.placeCenteredDimModal(roundedModal(boxW).Render(inner))
+}
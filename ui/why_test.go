@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/boyvinall/dirtygit/scanner"
+)
+
+func TestWhyOverlayListsReasons(t *testing.T) {
+	m := newTestModel()
+	m.width = 100
+	m.height = 30
+	m.repositories.AddResult("/r", scanner.RepoStatus{
+		Branch: "main",
+		Reasons: []scanner.Reason{
+			{Kind: scanner.ReasonChanges, Detail: "3 uncommitted changes"},
+			{Kind: scanner.ReasonStash, Detail: "1 stash entry", Rule: "stashes"},
+		},
+	})
+	m.repoList = m.visibleRepoPaths()
+
+	if _, _, handled := m.handleCommandKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}}); !handled || !m.whyOpen {
+		t.Fatal("w should open the why overlay")
+	}
+	out := m.View()
+	for _, want := range []string{"Why is this repository listed?", "3 uncommitted changes", "1 stash entry", "[stashes]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("overlay missing %q", want)
+		}
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.whyOpen {
+		t.Fatal("Esc should close the why overlay")
+	}
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// wordDiffMaxWords bounds each line of a -/+ pair compared word by word.
+	wordDiffMaxWords = 200
+	// wordDiffMaxChangedRatio is the share of a pair's text that may differ
+	// before the lines count as rewritten and nothing is emphasised.
+	wordDiffMaxChangedRatio = 0.6
+)
+
+// wordDiffHunk marks, for each hunk body line, the bytes after its -/+ prefix
+// that differ from the line it pairs with. In a run of deletions followed by
+// additions, the i-th deleted line pairs with the i-th added line; unpaired
+// lines are left nil.
+func wordDiffHunk(body []string) [][]bool {
+	out := make([][]bool, len(body))
+	var dels, adds []int
+	flush := func() {
+		for k := range min(len(dels), len(adds)) {
+			d, a := dels[k], adds[k]
+			out[d], out[a] = wordDiffPair(body[d][1:], body[a][1:])
+		}
+		dels, adds = nil, nil
+	}
+	for i, line := range body {
+		switch {
+		case strings.HasPrefix(line, "-"):
+			if len(adds) > 0 {
+				flush()
+			}
+			dels = append(dels, i)
+		case strings.HasPrefix(line, "+"):
+			adds = append(adds, i)
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file" belongs to the line before it.
+		default:
+			flush()
+		}
+	}
+	flush()
+	return out
+}
+
+// wordDiffPair compares two lines word by word (longest common subsequence) and
+// marks the bytes of each that the other does not share. Lines that differ too
+// much, or are too long to compare, get no marks.
+func wordDiffPair(a, b string) (changedA, changedB []bool) {
+	aw, bw := splitWords(a), splitWords(b)
+	if len(aw) > wordDiffMaxWords || len(bw) > wordDiffMaxWords {
+		return nil, nil
+	}
+	// lcs[i][j] is the common subsequence length of aw[i:] and bw[j:].
+	lcs := make([][]int, len(aw)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bw)+1)
+	}
+	for i := len(aw) - 1; i >= 0; i-- {
+		for j := len(bw) - 1; j >= 0; j-- {
+			if aw[i] == bw[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	keepA, keepB := make([]bool, len(aw)), make([]bool, len(bw))
+	for i, j := 0, 0; i < len(aw) && j < len(bw); {
+		switch {
+		case aw[i] == bw[j]:
+			keepA[i], keepB[j] = true, true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	changedA, nA := markChangedWords(aw, keepA, len(a))
+	changedB, nB := markChangedWords(bw, keepB, len(b))
+	if nA+nB == 0 || float64(nA+nB) > wordDiffMaxChangedRatio*float64(len(a)+len(b)) {
+		return nil, nil
+	}
+	return changedA, changedB
+}
+
+// markChangedWords marks the bytes of the words not kept and counts them.
+func markChangedWords(words []string, keep []bool, size int) ([]bool, int) {
+	changed := make([]bool, size)
+	n, off := 0, 0
+	for i, w := range words {
+		if !keep[i] {
+			for k := off; k < off+len(w); k++ {
+				changed[k] = true
+			}
+			n += len(w)
+		}
+		off += len(w)
+	}
+	return changed, n
+}
+
+// splitWords cuts s into runs of letters, digits and underscores, runs of
+// whitespace, and single other characters; joined they give back s.
+func splitWords(s string) []string {
+	class := func(r rune) int {
+		switch {
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		}
+		return 0
+	}
+	var words []string
+	for start := 0; start < len(s); {
+		r, size := utf8.DecodeRuneInString(s[start:])
+		end := start + size
+		if c := class(r); c != 0 {
+			for end < len(s) {
+				r, size := utf8.DecodeRuneInString(s[end:])
+				if class(r) != c {
+					break
+				}
+				end += size
+			}
+		}
+		words = append(words, s[start:end])
+		start = end
+	}
+	return words
+}
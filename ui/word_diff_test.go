@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// marked returns the bytes of s that changed marks.
+func marked(s string, changed []bool) string {
+	var b strings.Builder
+	for i := range len(s) {
+		if changed[i] {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func TestSplitWords(t *testing.T) {
+	got := splitWords("x := foo_bar(1)  // é")
+	want := []string{"x", " ", ":", "=", " ", "foo_bar", "(", "1", ")", "  ", "/", "/", " ", "é"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitWords = %q, want %q", got, want)
+	}
+}
+
+func TestWordDiffPairMarksChangedWords(t *testing.T) {
+	a, b := `return "hello", nil`, `return "hi", nil`
+	ca, cb := wordDiffPair(a, b)
+	if got := marked(a, ca); got != "hello" {
+		t.Fatalf("old side marks %q, want %q", got, "hello")
+	}
+	if got := marked(b, cb); got != "hi" {
+		t.Fatalf("new side marks %q, want %q", got, "hi")
+	}
+}
+
+func TestWordDiffPairSkipsRewrittenLines(t *testing.T) {
+	if ca, cb := wordDiffPair("alpha beta gamma", "one two three"); ca != nil || cb != nil {
+		t.Fatalf("rewritten line marks = %v, %v; want none", ca, cb)
+	}
+	if ca, cb := wordDiffPair("same", "same"); ca != nil || cb != nil {
+		t.Fatalf("identical line marks = %v, %v; want none", ca, cb)
+	}
+}
+
+func TestWordDiffHunkPairsRuns(t *testing.T) {
+	body := []string{
+		" ctx",
+		"-a := 1",
+		"-b := 2",
+		"+a := 10",
+		"+b := 20",
+		"+c := 3",
+		"\\ No newline at end of file",
+		"-solo := 1",
+	}
+	out := wordDiffHunk(body)
+	if got := marked(body[1][1:], out[1]); got != "1" {
+		t.Fatalf("first deleted line marks %q, want %q", got, "1")
+	}
+	if got := marked(body[4][1:], out[4]); got != "20" {
+		t.Fatalf("second added line marks %q, want %q", got, "20")
+	}
+	for _, i := range []int{0, 5, 6, 7} {
+		if out[i] != nil {
+			t.Fatalf("line %d %q should be unpaired, got marks %v", i, body[i], out[i])
+		}
+	}
+}